@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// RequireAdmin wraps next so it only runs when the request carries the
+// configured admin token in the X-Admin-Token header, gating the
+// credential-registry and webhook-subscription endpoints behind a shared
+// secret. If no ADMIN_TOKEN is configured, the endpoint refuses every
+// request rather than defaulting to open, since the credential endpoint
+// can redirect every route under an {id} to an attacker-controlled
+// backend and the webhook endpoint can send every object event to an
+// attacker-controlled URL.
+func (sc *S3Properties) RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Admin-Token")
+		if sc.adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(sc.adminToken)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-aws/interfaces"
+	"go-aws/utils"
+	"net/http"
+)
+
+// AddCredential registers (or replaces) a named S3 credential at runtime,
+// so a tenant bucket can be brought online without restarting the server.
+func (sc *S3Properties) AddCredential(w http.ResponseWriter, r *http.Request) {
+	var cred utils.S3Credential
+	if err := json.NewDecoder(r.Body).Decode(&cred); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := sc.RegisterCredential(cred); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(interfaces.ApiResponse{
+		Status:  200,
+		Payload: true,
+	})
+}
+
+// ListCredentialsHandler returns every registered credential, with secrets
+// stripped out.
+func (sc *S3Properties) ListCredentialsHandler(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(interfaces.ApiResponse{
+		Status:  200,
+		Payload: sc.ListCredentials(),
+	})
+}
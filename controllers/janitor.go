@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"go-aws/utils"
+	"log"
+	"time"
+)
+
+// RunJanitor periodically sweeps every registered credential's backend
+// for multipart uploads whose session has passed its TTL and aborts them,
+// so a client that abandons an upload doesn't leave orphaned parts
+// accruing storage charges forever. It blocks until ctx is cancelled, so
+// callers run it in its own goroutine.
+func (sc *S3Properties) RunJanitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sc.sweepExpiredSessions(ctx)
+			sc.sweepOrphanedUploads(ctx)
+		}
+	}
+}
+
+func (sc *S3Properties) sweepExpiredSessions(ctx context.Context) {
+	expired, err := sc.sessions.ListExpired(time.Now())
+	if err != nil {
+		log.Printf("janitor: listing expired upload sessions: %v", err)
+		return
+	}
+
+	for _, session := range expired {
+		sc.mu.RLock()
+		cred, ok := sc.credentials[session.CredentialID]
+		sc.mu.RUnlock()
+		if !ok {
+			log.Printf("janitor: upload %s references unknown credential %q, dropping session", session.UploadID, session.CredentialID)
+			sc.sessions.Delete(session.UploadID)
+			continue
+		}
+
+		backend, err := sc.backendFor(ctx, cred)
+		if err != nil {
+			log.Printf("janitor: building backend for credential %q: %v", cred.ID, err)
+			continue
+		}
+
+		if err := backend.AbortMultipartUpload(ctx, session.Key, session.UploadID); err != nil {
+			log.Printf("janitor: aborting stale upload %s (key %s): %v", session.UploadID, session.Key, err)
+			continue
+		}
+
+		if err := sc.sessions.Delete(session.UploadID); err != nil {
+			log.Printf("janitor: deleting session %s: %v", session.UploadID, err)
+		}
+	}
+}
+
+// sweepOrphanedUploads reconciles each backend's own view of in-progress
+// multipart uploads against the TTL, so an upload the session store never
+// learned about (a process restart with MemoryStore mid-upload, a session
+// record that failed to write, an upload started out-of-band) still gets
+// reclaimed instead of accruing storage charges forever.
+func (sc *S3Properties) sweepOrphanedUploads(ctx context.Context) {
+	sc.mu.RLock()
+	creds := make([]utils.S3Credential, 0, len(sc.credentials))
+	for _, cred := range sc.credentials {
+		creds = append(creds, cred)
+	}
+	sc.mu.RUnlock()
+
+	for _, cred := range creds {
+		backend, err := sc.backendFor(ctx, cred)
+		if err != nil {
+			log.Printf("janitor: building backend for credential %q: %v", cred.ID, err)
+			continue
+		}
+
+		uploads, err := backend.ListMultipartUploads(ctx)
+		if err != nil {
+			log.Printf("janitor: listing multipart uploads for credential %q: %v", cred.ID, err)
+			continue
+		}
+
+		for _, upload := range uploads {
+			if time.Since(upload.Initiated) < sc.uploadSessionTTL {
+				continue
+			}
+			if err := backend.AbortMultipartUpload(ctx, upload.Key, upload.UploadID); err != nil {
+				log.Printf("janitor: aborting orphaned upload %s (key %s) for credential %q: %v", upload.UploadID, upload.Key, cred.ID, err)
+				continue
+			}
+			sc.sessions.Delete(upload.UploadID)
+		}
+	}
+}
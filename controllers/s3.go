@@ -5,70 +5,234 @@ import (
 	"encoding/json"
 	"fmt"
 	"go-aws/interfaces"
+	"go-aws/sessions"
+	"go-aws/stores"
 	"go-aws/utils"
+	"go-aws/webhooks"
 	"net/http"
 	"sort"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 )
 
+// defaultCredentialID is the registry key for the single-bucket credential
+// built from the legacy S3_* environment variables. Every S3 route takes an
+// {id} path segment, so clients reach this credential as /default/....
+const defaultCredentialID = "default"
+
 // S3Controller handles S3 operations.
 type S3Properties struct {
-	s3Client   *s3.Client
-	bucketName string
+	mu sync.RWMutex
+	// credentials is the registry of every known credential, keyed by ID.
+	credentials map[string]utils.S3Credential
+	// backends caches one interfaces.ObjectStore per credential ID so
+	// requests don't pay for client construction on every call.
+	backends map[string]interfaces.ObjectStore
+	// storageBackend picks which ObjectStore implementation backends are
+	// built with (see utils.Config.StorageBackend).
+	storageBackend string
+	// streams tracks in-flight streaming uploads started via UploadStream,
+	// keyed by upload ID, so GET .../progress can report live byte counts.
+	streams   map[string]*streamProgress
+	streamsMu sync.RWMutex
+	// sessions persists multipart upload state so a client can resume
+	// after a crash and so the janitor can abort uploads that never
+	// completed.
+	sessions         sessions.Store
+	uploadSessionTTL time.Duration
+	// webhooks notifies downstream services when objects are uploaded,
+	// removed or renamed.
+	webhooks *webhooks.Dispatcher
+	// adminToken gates the credential-registry and webhook-subscription
+	// admin endpoints; see RequireAdmin.
+	adminToken string
 }
 
-// NewS3Controller initializes a new S3Controller with S3 client and bucket name.
+// S3Controller initializes a new S3Properties, seeding the credential
+// registry with the legacy single-bucket configuration plus anything
+// loaded from config.S3Credentials.
 func S3Controller(config *utils.Config) (*S3Properties, error) {
+	sessionStore, err := sessions.New(config.SessionStoreBackend, config.SessionStoreDSN)
+	if err != nil {
+		return nil, fmt.Errorf("creating session store: %w", err)
+	}
 
-	s3Client := s3.New(s3.Options{
-		Region:       config.S3Region,
-		BaseEndpoint: &config.S3Endpoint,
-		UsePathStyle: true,
-		Credentials: aws.NewCredentialsCache(
-			credentials.NewStaticCredentialsProvider(config.S3AccessKey, config.S3SecretKey, ""),
-		),
-	})
+	sc := &S3Properties{
+		credentials:      map[string]utils.S3Credential{},
+		backends:         map[string]interfaces.ObjectStore{},
+		storageBackend:   config.StorageBackend,
+		streams:          map[string]*streamProgress{},
+		sessions:         sessionStore,
+		uploadSessionTTL: config.UploadSessionTTL,
+		webhooks:         webhooks.NewDispatcher(config.WebhookSecret, config.WebhookURLs),
+		adminToken:       config.AdminToken,
+	}
+
+	if config.S3BucketName != "" {
+		sc.credentials[defaultCredentialID] = utils.S3Credential{
+			ID:         defaultCredentialID,
+			AccessKey:  config.S3AccessKey,
+			SecretKey:  config.S3SecretKey,
+			Region:     config.S3Region,
+			Endpoint:   config.S3Endpoint,
+			BucketName: config.S3BucketName,
+		}
+	}
+
+	for id, cred := range config.S3Credentials {
+		sc.credentials[id] = cred
+	}
+
+	return sc, nil
+}
+
+// RegisterCredential validates and stores a credential, making it available
+// to every route under /{id}. Re-registering an existing ID replaces it and
+// evicts the cached backend so the new settings take effect immediately.
+func (sc *S3Properties) RegisterCredential(cred utils.S3Credential) error {
+	if err := cred.Validate(); err != nil {
+		return err
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.credentials[cred.ID] = cred
+	delete(sc.backends, cred.ID)
+	return nil
+}
+
+// ListCredentials returns every registered credential, with secrets
+// stripped.
+func (sc *S3Properties) ListCredentials() []utils.S3Credential {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	out := make([]utils.S3Credential, 0, len(sc.credentials))
+	for _, cred := range sc.credentials {
+		cred.AccessKey = ""
+		cred.SecretKey = ""
+		out = append(out, cred)
+	}
+	return out
+}
+
+// resolve looks up the credential and cached object store backend for the
+// {id} path parameter of the given request, building and caching the
+// backend on first use.
+func (sc *S3Properties) resolve(r *http.Request) (utils.S3Credential, interfaces.ObjectStore, error) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		id = defaultCredentialID
+	}
+
+	cred, ok := sc.credentialByID(id)
+	if !ok {
+		return utils.S3Credential{}, nil, fmt.Errorf("unknown credential id %q", id)
+	}
+
+	backend, err := sc.backendFor(r.Context(), cred)
+	if err != nil {
+		return utils.S3Credential{}, nil, err
+	}
+	return cred, backend, nil
+}
 
-	return &S3Properties{
-		s3Client:   s3Client,
-		bucketName: config.S3BucketName,
-	}, nil
+// credentialByID looks up a registered credential by ID directly, for
+// callers that don't have an *http.Request carrying an {id} path
+// parameter to resolve against (e.g. the tokenized share-link downloader).
+func (sc *S3Properties) credentialByID(id string) (utils.S3Credential, bool) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	cred, ok := sc.credentials[id]
+	return cred, ok
+}
+
+// backendFor returns the cached interfaces.ObjectStore for cred, building
+// and caching one on first use. Unlike resolve, it doesn't need an
+// *http.Request, so the janitor can reuse it too.
+func (sc *S3Properties) backendFor(ctx context.Context, cred utils.S3Credential) (interfaces.ObjectStore, error) {
+	sc.mu.RLock()
+	backend, cached := sc.backends[cred.ID]
+	sc.mu.RUnlock()
+	if cached {
+		return backend, nil
+	}
+
+	backend, err := stores.New(ctx, sc.storageBackend, cred)
+	if err != nil {
+		return nil, err
+	}
+
+	sc.mu.Lock()
+	sc.backends[cred.ID] = backend
+	sc.mu.Unlock()
+
+	return backend, nil
+}
+
+// prefixedKey applies the credential's key prefix, so tenants sharing a
+// bucket can never read or write outside their own namespace.
+func prefixedKey(cred utils.S3Credential, key string) string {
+	if cred.Prefix == "" {
+		return key
+	}
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(cred.Prefix, "/"), strings.TrimPrefix(key, "/"))
 }
 
 // InitiateMultipartUpload starts a multipart upload and returns an upload ID.
 func (sc *S3Properties) InitiateMultipartUpload(w http.ResponseWriter, r *http.Request) {
-	var requestBody struct {
-		FileName string `json:"fileName"`
+	cred, store, err := sc.resolve(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
 	}
-	json.NewDecoder(r.Body).Decode(&requestBody)
 
-	params := &s3.CreateMultipartUploadInput{
-		Bucket: &sc.bucketName,
-		Key:    &requestBody.FileName,
+	var requestBody struct {
+		FileName      string `json:"fileName"`
+		PartSize      int64  `json:"partSize"`
+		ExpectedParts int    `json:"expectedParts"`
 	}
-	resp, err := sc.s3Client.CreateMultipartUpload(r.Context(), params)
+	json.NewDecoder(r.Body).Decode(&requestBody)
 
+	key := prefixedKey(cred, requestBody.FileName)
+	uploadID, err := store.CreateMultipartUpload(r.Context(), key)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+
+	sc.sessions.Create(&sessions.Session{
+		UploadID:      uploadID,
+		CredentialID:  mux.Vars(r)["id"],
+		Key:           key,
+		PartSize:      requestBody.PartSize,
+		ExpectedParts: requestBody.ExpectedParts,
+		CreatedAt:     time.Now(),
+		TTL:           sc.uploadSessionTTL,
+	})
+
 	json.NewEncoder(w).Encode(interfaces.ApiResponse{
 		Status: 200,
 		Payload: map[string]interface{}{
-			"id":  *resp.UploadId,
-			"key": *resp.Key,
+			"id":  uploadID,
+			"key": key,
 		},
 	})
 }
 
 func (sc *S3Properties) GeneratePresignedUrl(w http.ResponseWriter, r *http.Request) {
+	cred, store, err := sc.resolve(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
 	var requestBody struct {
 		FileKey string `json:"fileKey"`
 		FileId  string `json:"fileId"`
@@ -79,32 +243,27 @@ func (sc *S3Properties) GeneratePresignedUrl(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if !store.SupportsPresign() {
+		http.Error(w, "this credential's backend does not support presigned uploads; use /upload-stream instead", http.StatusNotImplemented)
+		return
+	}
+
+	key := prefixedKey(cred, requestBody.FileKey)
+
 	// Slice to hold each presigned URL and part number
 	urls := make([]interfaces.SignedUrl, requestBody.Parts)
 
 	for i := 1; i <= requestBody.Parts; i++ {
 		partNumber := int32(i)
-		// Prepare parameters for presigned URL
-		params := &s3.UploadPartInput{
-			Bucket:     &sc.bucketName,
-			Key:        &requestBody.FileKey,
-			UploadId:   &requestBody.FileId,
-			PartNumber: &partNumber,
-		}
 
-		// Generate presigned URL for each part
-		presignClient := s3.NewPresignClient(sc.s3Client)
-		presignedURL, err := presignClient.PresignUploadPart(context.TODO(), params, func(opts *s3.PresignOptions) {
-			opts.Expires = 15 * time.Minute
-		})
+		presignedURL, err := store.PresignUploadPart(r.Context(), key, requestBody.FileId, partNumber, 15*time.Minute)
 		if err != nil {
 			http.Error(w, "Failed to generate presigned URL: "+err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		// Append the URL to the response slice
 		urls[i-1] = interfaces.SignedUrl{
-			SignedUrl:  presignedURL.URL,
+			SignedUrl:  presignedURL,
 			PartNumber: partNumber,
 		}
 	}
@@ -117,6 +276,12 @@ func (sc *S3Properties) GeneratePresignedUrl(w http.ResponseWriter, r *http.Requ
 
 // CompleteMultipartUpload completes a multipart upload by assembling uploaded parts.
 func (sc *S3Properties) CompleteMultipartUpload(w http.ResponseWriter, r *http.Request) {
+	cred, store, err := sc.resolve(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
 	var requestBody struct {
 		FileKey string                `json:"fileKey"`
 		FileId  string                `json:"fileId"`
@@ -129,41 +294,31 @@ func (sc *S3Properties) CompleteMultipartUpload(w http.ResponseWriter, r *http.R
 		return *requestBody.Parts[i].PartNumber < *requestBody.Parts[j].PartNumber
 	})
 
-	// Convert UploadedPart to s3.CompletedPart
-	var completedParts []types.CompletedPart
-	for _, part := range requestBody.Parts {
-		completedParts = append(completedParts, types.CompletedPart{
-			ETag:       part.ETag,
-			PartNumber: part.PartNumber,
-		})
-	}
-
-	// Complete multipart upload
-	params := &s3.CompleteMultipartUploadInput{
-		Bucket:   &sc.bucketName,
-		Key:      &requestBody.FileKey,
-		UploadId: &requestBody.FileId,
-		MultipartUpload: &types.CompletedMultipartUpload{
-			Parts: completedParts,
-		},
-	}
-	_, err := sc.s3Client.CompleteMultipartUpload(r.Context(), params)
+	key := prefixedKey(cred, requestBody.FileKey)
+
+	etag, err := store.CompleteMultipartUpload(r.Context(), key, requestBody.FileId, requestBody.Parts)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	sc.sessions.Delete(requestBody.FileId)
 
-	// Get object size after upload completion
-	headParams := &s3.HeadObjectInput{
-		Bucket: &sc.bucketName,
-		Key:    &requestBody.FileKey,
-	}
-	headResp, err := sc.s3Client.HeadObject(r.Context(), headParams)
+	headResp, err := store.Head(r.Context(), key)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	sc.webhooks.Dispatch(webhooks.Event{
+		Type:      webhooks.EventObjectCompleted,
+		Bucket:    cred.BucketName,
+		Key:       key,
+		Size:      aws.ToInt64(headResp.ContentLength),
+		ETag:      etag,
+		Timestamp: time.Now(),
+		RequestID: uuid.NewString(),
+	})
+
 	// Send a response with the file key and size
 	json.NewEncoder(w).Encode(interfaces.ApiResponse{
 		Status: 200,
@@ -176,22 +331,23 @@ func (sc *S3Properties) CompleteMultipartUpload(w http.ResponseWriter, r *http.R
 
 // List lists objects in the S3 bucket based on prefix and delimiter.
 func (sc *S3Properties) List(w http.ResponseWriter, r *http.Request) {
-	prefix := r.URL.Query().Get("prefix")
+	cred, store, err := sc.resolve(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	prefix := prefixedKey(cred, r.URL.Query().Get("prefix"))
 	delimiter := r.URL.Query().Get("delimiter")
 
-	params := &s3.ListObjectsV2Input{
-		Bucket:    &sc.bucketName,
-		Prefix:    aws.String(prefix),
-		Delimiter: aws.String(delimiter),
-	}
-	resp, err := sc.s3Client.ListObjectsV2(r.Context(), params)
+	items, err := store.List(r.Context(), prefix, delimiter)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	objects := []types.Object{}
-	for _, item := range resp.Contents {
+	for _, item := range items {
 		if (delimiter == "/" && strings.HasSuffix(*item.Key, "/")) || (delimiter != "/" && !strings.HasSuffix(*item.Key, "/")) {
 			objects = append(objects, types.Object{
 				Key:  item.Key,
@@ -207,95 +363,64 @@ func (sc *S3Properties) List(w http.ResponseWriter, r *http.Request) {
 
 // Remove deletes an object from the S3 bucket.
 func (sc *S3Properties) Remove(w http.ResponseWriter, r *http.Request) {
-	fileKey := r.URL.Query().Get("fileKey")
-
-	params := &s3.DeleteObjectInput{
-		Bucket: &sc.bucketName,
-		Key:    aws.String(fileKey),
-	}
-	_, err := sc.s3Client.DeleteObject(r.Context(), params)
+	cred, store, err := sc.resolve(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
-	json.NewEncoder(w).Encode(interfaces.ApiResponse{
-		Status:  200,
-		Payload: true,
-	})
-}
 
-// Rename renames an object by copying it to a new key and deleting the old key.
-func (sc *S3Properties) Rename(w http.ResponseWriter, r *http.Request) {
-	oldFileKey := r.URL.Query().Get("oldFileKey")
-	newFileKey := r.URL.Query().Get("newFileKey")
+	fileKey := prefixedKey(cred, r.URL.Query().Get("fileKey"))
 
-	copyParams := &s3.CopyObjectInput{
-		Bucket:     &sc.bucketName,
-		CopySource: aws.String(fmt.Sprintf("%s/%s", sc.bucketName, oldFileKey)),
-		Key:        aws.String(newFileKey),
-	}
-	_, err := sc.s3Client.CopyObject(r.Context(), copyParams)
-	if err != nil {
+	if err := store.Delete(r.Context(), fileKey); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	deleteParams := &s3.DeleteObjectInput{
-		Bucket: &sc.bucketName,
-		Key:    aws.String(oldFileKey),
-	}
-	_, err = sc.s3Client.DeleteObject(r.Context(), deleteParams)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
+	sc.webhooks.Dispatch(webhooks.Event{
+		Type:      webhooks.EventObjectRemoved,
+		Bucket:    cred.BucketName,
+		Key:       fileKey,
+		Timestamp: time.Now(),
+		RequestID: uuid.NewString(),
+	})
+
 	json.NewEncoder(w).Encode(interfaces.ApiResponse{
 		Status:  200,
 		Payload: true,
 	})
 }
 
-// Share generates a presigned URL for an S3 object.
-func (sc *S3Properties) Share(w http.ResponseWriter, r *http.Request) {
-	// Extract query parameters
-	fileKey := r.URL.Query().Get("fileKey")
-	expiresInStr := r.URL.Query().Get("expiresIn")
-
-	// Validate the fileKey
-	if fileKey == "" {
-		http.Error(w, "fileKey is required", http.StatusBadRequest)
+// Rename renames an object by copying it to a new key and deleting the old key.
+func (sc *S3Properties) Rename(w http.ResponseWriter, r *http.Request) {
+	cred, store, err := sc.resolve(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
 
-	// Parse and validate expiration time
-	expiresIn, err := strconv.Atoi(expiresInStr)
-	if err != nil || expiresIn <= 0 {
-		http.Error(w, "Invalid or missing expiresIn parameter", http.StatusBadRequest)
+	oldFileKey := prefixedKey(cred, r.URL.Query().Get("oldFileKey"))
+	newFileKey := prefixedKey(cred, r.URL.Query().Get("newFileKey"))
+
+	if err := store.Copy(r.Context(), oldFileKey, newFileKey); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Prepare S3 get object input parameters
-	params := &s3.GetObjectInput{
-		Bucket:                     &sc.bucketName,
-		Key:                        aws.String(fileKey),
-		ResponseContentDisposition: aws.String("attachment"),
+	if err := store.Delete(r.Context(), oldFileKey); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	// Initialize the presign client
-	presignClient := s3.NewPresignClient(sc.s3Client)
-
-	// Generate the presigned URL
-	presignedURL, err := presignClient.PresignGetObject(r.Context(), params, func(opt *s3.PresignOptions) {
-		opt.Expires = time.Duration(expiresIn) * time.Second
+	sc.webhooks.Dispatch(webhooks.Event{
+		Type:      webhooks.EventObjectRenamed,
+		Bucket:    cred.BucketName,
+		Key:       newFileKey,
+		Timestamp: time.Now(),
+		RequestID: uuid.NewString(),
 	})
-	if err != nil {
-		http.Error(w, "Failed to generate presigned URL: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
 
-	// Send response with the presigned URL
 	json.NewEncoder(w).Encode(interfaces.ApiResponse{
 		Status:  200,
-		Payload: presignedURL.URL,
+		Payload: true,
 	})
 }
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-aws/interfaces"
+	"go-aws/sessions"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GenerateShareUrl mints a way to download an object without handing out
+// long-lived credentials. When the caller doesn't ask for a download
+// counter, an IP restriction or a fixed byte range and the backend can
+// presign, it returns a plain S3 presigned URL. Otherwise it mints an
+// opaque, single-purpose token backed by the session store and returns a
+// /d/{token} path, since a raw presigned URL has no way to enforce a
+// counter, an allow-list or a range once it's been handed out.
+func (sc *S3Properties) GenerateShareUrl(w http.ResponseWriter, r *http.Request) {
+	cred, store, err := sc.resolve(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var requestBody struct {
+		FileKey             string   `json:"fileKey"`
+		ExpiresIn           int      `json:"expiresIn"`
+		MaxDownloads        int      `json:"maxDownloads"`
+		IPAllowList         []string `json:"ipAllowList"`
+		ContentDisposition  string   `json:"contentDisposition"`
+		ResponseContentType string   `json:"responseContentType"`
+		RangeStart          int64    `json:"rangeStart"`
+		RangeEnd            int64    `json:"rangeEnd"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.FileKey == "" {
+		http.Error(w, "fileKey is required", http.StatusBadRequest)
+		return
+	}
+	if requestBody.ExpiresIn <= 0 {
+		http.Error(w, "Invalid or missing expiresIn parameter", http.StatusBadRequest)
+		return
+	}
+
+	key := prefixedKey(cred, requestBody.FileKey)
+	expiresIn := time.Duration(requestBody.ExpiresIn) * time.Second
+	restricted := requestBody.MaxDownloads > 0 || len(requestBody.IPAllowList) > 0 || requestBody.RangeStart > 0 || requestBody.RangeEnd > 0
+
+	if !restricted && store.SupportsPresign() {
+		presignedURL, err := store.PresignGet(r.Context(), key, expiresIn, requestBody.ContentDisposition)
+		if err != nil {
+			http.Error(w, "Failed to generate presigned URL: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(interfaces.ApiResponse{
+			Status:  200,
+			Payload: presignedURL,
+		})
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		id = defaultCredentialID
+	}
+
+	token := &sessions.ShareToken{
+		Token:               uuid.NewString(),
+		CredentialID:        id,
+		Key:                 key,
+		ExpiresAt:           time.Now().Add(expiresIn),
+		MaxDownloads:        requestBody.MaxDownloads,
+		IPAllowList:         requestBody.IPAllowList,
+		ContentDisposition:  requestBody.ContentDisposition,
+		ResponseContentType: requestBody.ResponseContentType,
+		RangeStart:          requestBody.RangeStart,
+		RangeEnd:            requestBody.RangeEnd,
+	}
+	if err := sc.sessions.CreateShareToken(token); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(interfaces.ApiResponse{
+		Status:  200,
+		Payload: fmt.Sprintf("/d/%s", token.Token),
+	})
+}
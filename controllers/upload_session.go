@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-aws/interfaces"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// GetUploadSession reports which part numbers have been acknowledged for
+// an in-flight multipart upload, so a client that lost its connection can
+// resume from the first missing part instead of starting over.
+func (sc *S3Properties) GetUploadSession(w http.ResponseWriter, r *http.Request) {
+	uploadID := mux.Vars(r)["uploadId"]
+
+	session, ok, err := sc.sessions.Get(uploadID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.Error(w, "unknown upload session", http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(interfaces.ApiResponse{
+		Status: 200,
+		Payload: map[string]interface{}{
+			"uploadId":          session.UploadID,
+			"key":               session.Key,
+			"expectedParts":     session.ExpectedParts,
+			"acknowledgedParts": session.AcknowledgedParts(),
+		},
+	})
+}
+
+// RecordUploadSessionPart records that a part finished uploading, so a
+// later GetUploadSession call (from this client or a resumed one) knows
+// not to re-send it.
+func (sc *S3Properties) RecordUploadSessionPart(w http.ResponseWriter, r *http.Request) {
+	uploadID := mux.Vars(r)["uploadId"]
+
+	var requestBody struct {
+		PartNumber int32  `json:"partNumber"`
+		ETag       string `json:"eTag"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := sc.sessions.RecordPart(uploadID, requestBody.PartNumber, requestBody.ETag); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(interfaces.ApiResponse{
+		Status:  200,
+		Payload: true,
+	})
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-aws/hashing"
+	"go-aws/interfaces"
+	"go-aws/webhooks"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// minStreamPartSize and maxStreamPartSize bound the part size a caller may
+// request, matching the 5-16 MiB range this feature targets.
+const (
+	minStreamPartSize     = 5 * 1024 * 1024
+	maxStreamPartSize     = 16 * 1024 * 1024
+	defaultStreamPartSize = 8 * 1024 * 1024
+)
+
+// parsePartSize validates a caller-supplied part size against the
+// supported range.
+func parsePartSize(raw string) (int, error) {
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, err
+	}
+	if n < minStreamPartSize || n > maxStreamPartSize {
+		return 0, fmt.Errorf("partSize must be between %d and %d bytes", minStreamPartSize, maxStreamPartSize)
+	}
+	return n, nil
+}
+
+// streamProgress tracks how many bytes of a streamed upload have been
+// written to S3 so far, for polling by GET /{id}/upload-stream/{uploadId}/progress.
+type streamProgress struct {
+	bytesWritten int64
+	done         bool
+}
+
+// UploadStream accepts a raw request body and pipes it to S3 via
+// UploadPart calls, computing MD5, SHA-1, SHA-256 and CRC32C as the bytes
+// flow through. It exists for clients that cannot presign a direct upload
+// (browsers behind restrictive proxies, some mobile runtimes).
+func (sc *S3Properties) UploadStream(w http.ResponseWriter, r *http.Request) {
+	cred, store, err := sc.resolve(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	fileKey := r.URL.Query().Get("fileKey")
+	if fileKey == "" {
+		http.Error(w, "fileKey is required", http.StatusBadRequest)
+		return
+	}
+	key := prefixedKey(cred, fileKey)
+
+	partSize := defaultStreamPartSize
+	if v := r.URL.Query().Get("partSize"); v != "" {
+		if n, err := parsePartSize(v); err == nil {
+			partSize = n
+		}
+	}
+
+	uploadID := uuid.NewString()
+	progress := &streamProgress{}
+	sc.streamsMu.Lock()
+	sc.streams[uploadID] = progress
+	sc.streamsMu.Unlock()
+
+	s3UploadID, err := store.CreateMultipartUpload(r.Context(), key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	mh := hashing.NewMultiHash()
+	var totalSize int64
+	var completedParts []types.CompletedPart
+	partNumber := int32(1)
+
+	abort := func() {
+		store.AbortMultipartUpload(r.Context(), key, s3UploadID)
+		mh.Close()
+	}
+
+	buf := make([]byte, partSize)
+	for {
+		n, readErr := io.ReadFull(r.Body, buf)
+		if n > 0 {
+			chunk := buf[:n]
+			mh.Write(chunk)
+
+			etag, err := store.UploadPart(r.Context(), key, s3UploadID, partNumber, bytes.NewReader(chunk))
+			if err != nil {
+				abort()
+				http.Error(w, "upload failed: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			pn := partNumber
+			completedParts = append(completedParts, types.CompletedPart{
+				ETag:       &etag,
+				PartNumber: &pn,
+			})
+
+			totalSize += int64(n)
+			sc.streamsMu.Lock()
+			progress.bytesWritten = totalSize
+			sc.streamsMu.Unlock()
+			partNumber++
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			abort()
+			http.Error(w, "reading request body: "+readErr.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			abort()
+			http.Error(w, "upload cancelled", http.StatusRequestTimeout)
+			return
+		default:
+		}
+	}
+
+	mh.Close()
+	digests := mh.Sum()
+
+	etag, err := store.CompleteMultipartUpload(r.Context(), key, s3UploadID, completedParts)
+	if err != nil {
+		// mh is already closed, so only the upload itself is aborted here.
+		store.AbortMultipartUpload(r.Context(), key, s3UploadID)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sc.streamsMu.Lock()
+	progress.done = true
+	sc.streamsMu.Unlock()
+
+	sc.webhooks.Dispatch(webhooks.Event{
+		Type:      webhooks.EventObjectCompleted,
+		Bucket:    cred.BucketName,
+		Key:       key,
+		Size:      totalSize,
+		ETag:      etag,
+		Timestamp: time.Now(),
+		RequestID: uuid.NewString(),
+	})
+
+	json.NewEncoder(w).Encode(interfaces.ApiResponse{
+		Status: 200,
+		Payload: map[string]interface{}{
+			"id":     uploadID,
+			"key":    fileKey,
+			"size":   totalSize,
+			"etag":   etag,
+			"md5":    digests.MD5,
+			"sha1":   digests.SHA1,
+			"sha256": digests.SHA256,
+			"crc32c": digests.CRC32C,
+		},
+	})
+}
+
+// UploadStreamProgress reports how many bytes of a streamed upload have
+// reached S3 so far.
+func (sc *S3Properties) UploadStreamProgress(w http.ResponseWriter, r *http.Request) {
+	uploadID := mux.Vars(r)["uploadId"]
+
+	sc.streamsMu.RLock()
+	progress, ok := sc.streams[uploadID]
+	sc.streamsMu.RUnlock()
+
+	if !ok {
+		http.Error(w, "unknown upload id", http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(interfaces.ApiResponse{
+		Status: 200,
+		Payload: map[string]interface{}{
+			"bytesWritten": progress.bytesWritten,
+			"done":         progress.done,
+		},
+	})
+}
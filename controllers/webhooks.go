@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-aws/interfaces"
+	"go-aws/webhooks"
+	"net/http"
+)
+
+// SubscribeWebhook registers a new callback URL at runtime, optionally
+// filtered to a key prefix and/or a set of event types, so downstream
+// services can start reacting to uploads without a restart.
+func (sc *S3Properties) SubscribeWebhook(w http.ResponseWriter, r *http.Request) {
+	var sub webhooks.Subscription
+	if err := json.NewDecoder(r.Body).Decode(&sub); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if sub.URL == "" {
+		http.Error(w, "url is required", http.StatusBadRequest)
+		return
+	}
+
+	sc.webhooks.Subscribe(sub)
+
+	json.NewEncoder(w).Encode(interfaces.ApiResponse{
+		Status:  200,
+		Payload: true,
+	})
+}
@@ -0,0 +1,115 @@
+// Package hashing provides a writer that computes several digests of a
+// byte stream in parallel, so hashing never serializes with the I/O that
+// produces the bytes.
+package hashing
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"hash/crc32"
+	"io"
+	"sync"
+)
+
+// Digests holds the hex-encoded result of each algorithm a MultiHash
+// tracks.
+type Digests struct {
+	MD5    string
+	SHA1   string
+	SHA256 string
+	CRC32C string
+}
+
+// MultiHash is an io.Writer that fans every Write out to MD5, SHA-1,
+// SHA-256 and CRC32C (Castagnoli) simultaneously. Each algorithm runs on
+// its own goroutine, fed through a buffered channel of 32 chunks, so
+// hashing overlaps with the network write that produces the bytes. Write
+// feeds the channels one after another, so once a slow hash's buffer is
+// full, Write (and with it every other hash) waits for it.
+type MultiHash struct {
+	chans []chan []byte
+	wg    sync.WaitGroup
+
+	mu      sync.Mutex
+	results Digests
+}
+
+// NewMultiHash starts the goroutines and returns a ready-to-use MultiHash.
+// Callers must call Close once writing is done (or has failed) to release
+// the goroutines, then Sum to read the final digests.
+func NewMultiHash() *MultiHash {
+	algorithms := []struct {
+		name string
+		h    hash.Hash
+	}{
+		{"md5", md5.New()},
+		{"sha1", sha1.New()},
+		{"sha256", sha256.New()},
+		{"crc32c", crc32.New(crc32.MakeTable(crc32.Castagnoli))},
+	}
+
+	mh := &MultiHash{
+		chans: make([]chan []byte, len(algorithms)),
+	}
+
+	for i, algo := range algorithms {
+		ch := make(chan []byte, 32)
+		mh.chans[i] = ch
+		mh.wg.Add(1)
+
+		go func(name string, h hash.Hash, ch chan []byte) {
+			defer mh.wg.Done()
+			for chunk := range ch {
+				h.Write(chunk)
+			}
+			sum := hex.EncodeToString(h.Sum(nil))
+			mh.mu.Lock()
+			switch name {
+			case "md5":
+				mh.results.MD5 = sum
+			case "sha1":
+				mh.results.SHA1 = sum
+			case "sha256":
+				mh.results.SHA256 = sum
+			case "crc32c":
+				mh.results.CRC32C = sum
+			}
+			mh.mu.Unlock()
+		}(algo.name, algo.h, ch)
+	}
+
+	return mh
+}
+
+// Write implements io.Writer, copying p to every algorithm's channel. The
+// caller retains ownership of p's backing array after Write returns, so a
+// defensive copy is handed to each goroutine.
+func (mh *MultiHash) Write(p []byte) (int, error) {
+	for _, ch := range mh.chans {
+		chunk := make([]byte, len(p))
+		copy(chunk, p)
+		ch <- chunk
+	}
+	return len(p), nil
+}
+
+// Close stops accepting writes and waits for every algorithm to finish
+// hashing whatever was already queued.
+func (mh *MultiHash) Close() {
+	for _, ch := range mh.chans {
+		close(ch)
+	}
+	mh.wg.Wait()
+}
+
+// Sum returns the final digests. It must only be called after Close.
+func (mh *MultiHash) Sum() Digests {
+	mh.mu.Lock()
+	defer mh.mu.Unlock()
+	return mh.results
+}
+
+var _ io.Writer = (*MultiHash)(nil)
@@ -0,0 +1,65 @@
+package hashing
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/crc32"
+	"testing"
+)
+
+func TestMultiHashSum(t *testing.T) {
+	data := []byte("the quick brown fox jumps over the lazy dog")
+
+	mh := NewMultiHash()
+	if _, err := mh.Write(data[:10]); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if _, err := mh.Write(data[10:]); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	mh.Close()
+
+	got := mh.Sum()
+
+	md5Sum := md5.Sum(data)
+	sha1Sum := sha1.Sum(data)
+	sha256Sum := sha256.Sum256(data)
+	crc32cSum := crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))
+
+	want := Digests{
+		MD5:    hex.EncodeToString(md5Sum[:]),
+		SHA1:   hex.EncodeToString(sha1Sum[:]),
+		SHA256: hex.EncodeToString(sha256Sum[:]),
+		CRC32C: hex.EncodeToString([]byte{byte(crc32cSum >> 24), byte(crc32cSum >> 16), byte(crc32cSum >> 8), byte(crc32cSum)}),
+	}
+
+	if got != want {
+		t.Fatalf("Sum() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMultiHashEmpty(t *testing.T) {
+	mh := NewMultiHash()
+	mh.Close()
+
+	got := mh.Sum()
+
+	emptyMD5 := md5.Sum(nil)
+	emptySHA1 := sha1.Sum(nil)
+	emptySHA256 := sha256.Sum256(nil)
+
+	if got.MD5 != hex.EncodeToString(emptyMD5[:]) {
+		t.Errorf("MD5 = %q, want %q", got.MD5, hex.EncodeToString(emptyMD5[:]))
+	}
+	if got.SHA1 != hex.EncodeToString(emptySHA1[:]) {
+		t.Errorf("SHA1 = %q, want %q", got.SHA1, hex.EncodeToString(emptySHA1[:]))
+	}
+	if got.SHA256 != hex.EncodeToString(emptySHA256[:]) {
+		t.Errorf("SHA256 = %q, want %q", got.SHA256, hex.EncodeToString(emptySHA256[:]))
+	}
+	if got.CRC32C != "00000000" {
+		t.Errorf("CRC32C = %q, want %q", got.CRC32C, "00000000")
+	}
+}
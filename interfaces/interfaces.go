@@ -0,0 +1,17 @@
+// Package interfaces holds the shared response/request shapes and seams
+// used across controllers, so handlers and their backends agree on a
+// common vocabulary without importing each other's packages.
+package interfaces
+
+// ApiResponse is the envelope every handler in this service replies with.
+type ApiResponse struct {
+	Status  int         `json:"status"`
+	Payload interface{} `json:"payload"`
+}
+
+// SignedUrl pairs a presigned upload URL with the part number it belongs
+// to, so clients can upload parts out of order and still reassemble them.
+type SignedUrl struct {
+	SignedUrl  string `json:"signedUrl"`
+	PartNumber int32  `json:"partNumber"`
+}
@@ -0,0 +1,65 @@
+package interfaces
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// MultipartUploadInfo describes one in-progress multipart upload as
+// reported by ObjectStore.ListMultipartUploads, enough for the session
+// janitor to decide whether it has gone stale.
+type MultipartUploadInfo struct {
+	Key       string
+	UploadID  string
+	Initiated time.Time
+}
+
+// ObjectRange is the result of a ranged or whole-object download, returned
+// by ObjectStore.Get. Callers must close Body.
+type ObjectRange struct {
+	Body          io.ReadCloser
+	ContentLength int64
+	// ContentRange is the backend's "bytes start-end/total" response
+	// header, empty when rangeHeader was empty and the whole object was
+	// returned.
+	ContentRange string
+	ContentType  string
+}
+
+// ObjectStore is the seam between the S3 route handlers and whatever
+// backend actually holds the bytes. Every handler is written against this
+// interface instead of a concrete *s3.Client, so adding a new provider
+// (MinIO, COS, OSS, a gocloud.dev/blob bucket, ...) never touches a route.
+type ObjectStore interface {
+	CreateMultipartUpload(ctx context.Context, key string) (uploadID string, err error)
+	// UploadPart is only used by the streaming proxy upload path
+	// (clients that cannot presign); the presigned flow uses
+	// PresignUploadPart instead and never calls this server-side.
+	UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.Reader) (etag string, err error)
+	PresignUploadPart(ctx context.Context, key, uploadID string, partNumber int32, expires time.Duration) (url string, err error)
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []types.CompletedPart) (etag string, err error)
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+	// ListMultipartUploads reports every multipart upload the backend
+	// still has open, so the session janitor can abort ones that never
+	// completed and are now just accruing storage charges.
+	ListMultipartUploads(ctx context.Context) ([]MultipartUploadInfo, error)
+	List(ctx context.Context, prefix, delimiter string) ([]types.Object, error)
+	Delete(ctx context.Context, key string) error
+	Copy(ctx context.Context, sourceKey, destinationKey string) error
+	Head(ctx context.Context, key string) (*s3.HeadObjectOutput, error)
+	PresignGet(ctx context.Context, key string, expires time.Duration, responseContentDisposition string) (url string, err error)
+	// Get streams an object's bytes server-side, honoring rangeHeader (a
+	// raw HTTP Range header value, e.g. "bytes=0-1023") when non-empty.
+	// Used by the tokenized share-link downloader, which can't hand a
+	// client a presigned URL without losing control over download counts
+	// and IP restrictions.
+	Get(ctx context.Context, key, rangeHeader string) (*ObjectRange, error)
+	// SupportsPresign reports whether this backend can mint presigned
+	// URLs directly. Backends that can't fall back to the streaming
+	// proxy upload/download path instead.
+	SupportsPresign() bool
+}
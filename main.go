@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"go-aws/controllers"
 	"go-aws/routes" // replace with actual package path
@@ -24,7 +25,12 @@ func main() {
 		log.Fatalf("Error loading config: %v", err)
 	}
 
-	s3Controller, _ := controllers.S3Controller(config)
+	s3Controller, err := controllers.S3Controller(config)
+	if err != nil {
+		log.Fatalf("Error creating S3 controller: %v", err)
+	}
+
+	go s3Controller.RunJanitor(context.Background(), config.JanitorInterval)
 
 	router := routes.InitRoutes(s3Controller)
 
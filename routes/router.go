@@ -9,14 +9,31 @@ import (
 func InitRoutes(s3Controller *controllers.S3Properties) *mux.Router {
 	router := mux.NewRouter()
 
-	// s3 routes
-	router.HandleFunc("/initiate-multipart-upload", s3Controller.InitiateMultipartUpload).Methods("POST")
-	router.HandleFunc("/generate-presigned-urls", s3Controller.GeneratePresignedUrl).Methods("POST")
-	router.HandleFunc("/complete-multipart-upload", s3Controller.CompleteMultipartUpload).Methods("POST")
-	router.HandleFunc("/list-documents", s3Controller.List).Methods("GET")
-	router.HandleFunc("/remove-document", s3Controller.Remove).Methods("DELETE")
-	router.HandleFunc("/rename-document", s3Controller.Rename).Methods("PATCH")
-	router.HandleFunc("/generate-share-url", s3Controller.Share).Methods("GET")
+	// credential admin routes, gated behind ADMIN_TOKEN since they can
+	// redirect every route under an {id} to an attacker-controlled backend
+	router.HandleFunc("/credentials", s3Controller.RequireAdmin(s3Controller.AddCredential)).Methods("POST")
+	router.HandleFunc("/credentials", s3Controller.RequireAdmin(s3Controller.ListCredentialsHandler)).Methods("GET")
+
+	// webhook admin routes, same gating as the credential routes above
+	router.HandleFunc("/webhooks/subscribe", s3Controller.RequireAdmin(s3Controller.SubscribeWebhook)).Methods("POST")
+
+	// s3 routes, scoped to a credential id so one server can front several
+	// tenants/buckets at once
+	router.HandleFunc("/{id}/initiate-multipart-upload", s3Controller.InitiateMultipartUpload).Methods("POST")
+	router.HandleFunc("/{id}/generate-presigned-urls", s3Controller.GeneratePresignedUrl).Methods("POST")
+	router.HandleFunc("/{id}/complete-multipart-upload", s3Controller.CompleteMultipartUpload).Methods("POST")
+	router.HandleFunc("/{id}/list-documents", s3Controller.List).Methods("GET")
+	router.HandleFunc("/{id}/remove-document", s3Controller.Remove).Methods("DELETE")
+	router.HandleFunc("/{id}/rename-document", s3Controller.Rename).Methods("PATCH")
+	router.HandleFunc("/{id}/generate-share-url", s3Controller.GenerateShareUrl).Methods("POST")
+	router.HandleFunc("/{id}/upload-stream", s3Controller.UploadStream).Methods("POST")
+	router.HandleFunc("/{id}/upload-stream/{uploadId}/progress", s3Controller.UploadStreamProgress).Methods("GET")
+	router.HandleFunc("/{id}/upload-session/{uploadId}", s3Controller.GetUploadSession).Methods("GET")
+	router.HandleFunc("/{id}/upload-session/{uploadId}/parts", s3Controller.RecordUploadSessionPart).Methods("POST")
+
+	// token-gated download links minted by GenerateShareUrl; not scoped
+	// under /{id} since the token itself carries the credential reference
+	router.HandleFunc("/d/{token}", s3Controller.Download).Methods("GET")
 
 	return router
 }
@@ -0,0 +1,181 @@
+package sessions
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var sessionsBucket = []byte("upload_sessions")
+var shareTokensBucket = []byte("share_tokens")
+
+// BoltStore persists sessions to a BoltDB file, so they survive a process
+// restart on a single node.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a BoltDB file at path.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening bolt db: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(sessionsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(shareTokensBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating sessions buckets: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func (b *BoltStore) Create(session *Session) error {
+	if session.Parts == nil {
+		session.Parts = map[int32]string{}
+	}
+	return b.put(session)
+}
+
+func (b *BoltStore) Get(uploadID string) (*Session, bool, error) {
+	var session *Session
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(sessionsBucket).Get([]byte(uploadID))
+		if data == nil {
+			return nil
+		}
+		session = &Session{}
+		return json.Unmarshal(data, session)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return session, session != nil, nil
+}
+
+func (b *BoltStore) RecordPart(uploadID string, partNumber int32, etag string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sessionsBucket)
+		data := bucket.Get([]byte(uploadID))
+		if data == nil {
+			return fmt.Errorf("unknown upload session %q", uploadID)
+		}
+		var session Session
+		if err := json.Unmarshal(data, &session); err != nil {
+			return err
+		}
+		if session.Parts == nil {
+			session.Parts = map[int32]string{}
+		}
+		session.Parts[partNumber] = etag
+
+		updated, err := json.Marshal(&session)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(session.UploadID), updated)
+	})
+}
+
+func (b *BoltStore) Delete(uploadID string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).Delete([]byte(uploadID))
+	})
+}
+
+func (b *BoltStore) ListExpired(now time.Time) ([]*Session, error) {
+	var expired []*Session
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).ForEach(func(_, data []byte) error {
+			var session Session
+			if err := json.Unmarshal(data, &session); err != nil {
+				return err
+			}
+			if session.Expired(now) {
+				expired = append(expired, &session)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+func (b *BoltStore) put(session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).Put([]byte(session.UploadID), data)
+	})
+}
+
+func (b *BoltStore) CreateShareToken(token *ShareToken) error {
+	return b.putShareToken(token)
+}
+
+func (b *BoltStore) GetShareToken(token string) (*ShareToken, bool, error) {
+	var shareToken *ShareToken
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(shareTokensBucket).Get([]byte(token))
+		if data == nil {
+			return nil
+		}
+		shareToken = &ShareToken{}
+		return json.Unmarshal(data, shareToken)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return shareToken, shareToken != nil, nil
+}
+
+func (b *BoltStore) IncrementShareTokenDownloads(token string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(shareTokensBucket)
+		data := bucket.Get([]byte(token))
+		if data == nil {
+			return fmt.Errorf("unknown share token %q", token)
+		}
+		var shareToken ShareToken
+		if err := json.Unmarshal(data, &shareToken); err != nil {
+			return err
+		}
+		shareToken.DownloadCount++
+
+		updated, err := json.Marshal(&shareToken)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(shareToken.Token), updated)
+	})
+}
+
+func (b *BoltStore) DeleteShareToken(token string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(shareTokensBucket).Delete([]byte(token))
+	})
+}
+
+func (b *BoltStore) putShareToken(token *ShareToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(shareTokensBucket).Put([]byte(token.Token), data)
+	})
+}
+
+var _ Store = (*BoltStore)(nil)
@@ -0,0 +1,19 @@
+package sessions
+
+import "fmt"
+
+// New builds the Store for the given backend name. dsn is the BoltDB file
+// path for "bolt" or the "host:port" address for "redis"; it's ignored for
+// "memory".
+func New(backend, dsn string) (Store, error) {
+	switch backend {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "bolt":
+		return NewBoltStore(dsn)
+	case "redis":
+		return NewRedisStore(dsn), nil
+	default:
+		return nil, fmt.Errorf("unknown session store backend %q", backend)
+	}
+}
@@ -0,0 +1,107 @@
+package sessions
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MemoryStore is the default Store: an in-process map. Sessions don't
+// survive a restart, which is acceptable for single-instance deployments
+// but not for anything load-balanced; use BoltStore or RedisStore there.
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+
+	shareTokens map[string]*ShareToken
+}
+
+// NewMemoryStore returns a ready-to-use MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions:    map[string]*Session{},
+		shareTokens: map[string]*ShareToken{},
+	}
+}
+
+func (m *MemoryStore) Create(session *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if session.Parts == nil {
+		session.Parts = map[int32]string{}
+	}
+	m.sessions[session.UploadID] = session
+	return nil
+}
+
+func (m *MemoryStore) Get(uploadID string) (*Session, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.sessions[uploadID]
+	return session, ok, nil
+}
+
+func (m *MemoryStore) RecordPart(uploadID string, partNumber int32, etag string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.sessions[uploadID]
+	if !ok {
+		return fmt.Errorf("unknown upload session %q", uploadID)
+	}
+	session.Parts[partNumber] = etag
+	return nil
+}
+
+func (m *MemoryStore) Delete(uploadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, uploadID)
+	return nil
+}
+
+func (m *MemoryStore) ListExpired(now time.Time) ([]*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expired []*Session
+	for _, session := range m.sessions {
+		if session.Expired(now) {
+			expired = append(expired, session)
+		}
+	}
+	return expired, nil
+}
+
+func (m *MemoryStore) CreateShareToken(token *ShareToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.shareTokens[token.Token] = token
+	return nil
+}
+
+func (m *MemoryStore) GetShareToken(token string) (*ShareToken, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	shareToken, ok := m.shareTokens[token]
+	return shareToken, ok, nil
+}
+
+func (m *MemoryStore) IncrementShareTokenDownloads(token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	shareToken, ok := m.shareTokens[token]
+	if !ok {
+		return fmt.Errorf("unknown share token %q", token)
+	}
+	shareToken.DownloadCount++
+	return nil
+}
+
+func (m *MemoryStore) DeleteShareToken(token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.shareTokens, token)
+	return nil
+}
+
+var _ Store = (*MemoryStore)(nil)
@@ -0,0 +1,155 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisSessionsKey = "go-aws:upload-sessions"
+const redisShareTokensKey = "go-aws:share-tokens"
+
+// recordPartScript applies a part ack to a session's Parts map inside
+// Redis itself, so the read-modify-write can't race with a concurrent ack
+// for the same upload the way a client-side HGET+HSET pair would.
+var recordPartScript = redis.NewScript(`
+local data = redis.call('HGET', KEYS[1], ARGV[1])
+if not data then
+	return redis.error_reply('unknown upload session ' .. ARGV[1])
+end
+local session = cjson.decode(data)
+if session.Parts == nil or session.Parts == cjson.null then
+	session.Parts = {}
+end
+session.Parts[ARGV[2]] = ARGV[3]
+redis.call('HSET', KEYS[1], ARGV[1], cjson.encode(session))
+return 1
+`)
+
+// incrementDownloadsScript bumps a share token's DownloadCount inside Redis
+// itself, so two concurrent downloads of the same token can't both read
+// and write back the same count.
+var incrementDownloadsScript = redis.NewScript(`
+local data = redis.call('HGET', KEYS[1], ARGV[1])
+if not data then
+	return redis.error_reply('unknown share token ' .. ARGV[1])
+end
+local token = cjson.decode(data)
+token.DownloadCount = (token.DownloadCount or 0) + 1
+redis.call('HSET', KEYS[1], ARGV[1], cjson.encode(token))
+return token.DownloadCount
+`)
+
+// RedisStore persists sessions in a single Redis hash, so multiple server
+// instances behind a load balancer share one view of in-flight uploads.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore builds a RedisStore against the given addr (host:port).
+func NewRedisStore(addr string) *RedisStore {
+	return &RedisStore{client: redis.NewClient(&redis.Options{Addr: addr})}
+}
+
+func (r *RedisStore) Create(session *Session) error {
+	if session.Parts == nil {
+		session.Parts = map[int32]string{}
+	}
+	return r.put(session)
+}
+
+func (r *RedisStore) Get(uploadID string) (*Session, bool, error) {
+	data, err := r.client.HGet(context.Background(), redisSessionsKey, uploadID).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, false, err
+	}
+	return &session, true, nil
+}
+
+func (r *RedisStore) RecordPart(uploadID string, partNumber int32, etag string) error {
+	return recordPartScript.Run(context.Background(), r.client,
+		[]string{redisSessionsKey}, uploadID, fmt.Sprintf("%d", partNumber), etag,
+	).Err()
+}
+
+func (r *RedisStore) Delete(uploadID string) error {
+	return r.client.HDel(context.Background(), redisSessionsKey, uploadID).Err()
+}
+
+func (r *RedisStore) ListExpired(now time.Time) ([]*Session, error) {
+	all, err := r.client.HGetAll(context.Background(), redisSessionsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []*Session
+	for _, data := range all {
+		var session Session
+		if err := json.Unmarshal([]byte(data), &session); err != nil {
+			return nil, err
+		}
+		if session.Expired(now) {
+			expired = append(expired, &session)
+		}
+	}
+	return expired, nil
+}
+
+func (r *RedisStore) put(session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return r.client.HSet(context.Background(), redisSessionsKey, session.UploadID, data).Err()
+}
+
+func (r *RedisStore) CreateShareToken(token *ShareToken) error {
+	return r.putShareToken(token)
+}
+
+func (r *RedisStore) GetShareToken(token string) (*ShareToken, bool, error) {
+	data, err := r.client.HGet(context.Background(), redisShareTokensKey, token).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var shareToken ShareToken
+	if err := json.Unmarshal(data, &shareToken); err != nil {
+		return nil, false, err
+	}
+	return &shareToken, true, nil
+}
+
+func (r *RedisStore) IncrementShareTokenDownloads(token string) error {
+	return incrementDownloadsScript.Run(context.Background(), r.client,
+		[]string{redisShareTokensKey}, token,
+	).Err()
+}
+
+func (r *RedisStore) DeleteShareToken(token string) error {
+	return r.client.HDel(context.Background(), redisShareTokensKey, token).Err()
+}
+
+func (r *RedisStore) putShareToken(token *ShareToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return r.client.HSet(context.Background(), redisShareTokensKey, token.Token, data).Err()
+}
+
+var _ Store = (*RedisStore)(nil)
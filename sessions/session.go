@@ -0,0 +1,106 @@
+// Package sessions persists in-progress multipart upload state so a
+// client that crashes or loses connectivity mid-upload can ask what it
+// already sent and resume from there instead of starting over.
+package sessions
+
+import "time"
+
+// Session records everything needed to resume or expire a single
+// multipart upload.
+type Session struct {
+	UploadID      string
+	CredentialID  string
+	Key           string
+	PartSize      int64
+	ExpectedParts int
+	// Parts maps part number to the ETag S3 returned for it, recorded as
+	// each part finishes uploading.
+	Parts     map[int32]string
+	CreatedAt time.Time
+	TTL       time.Duration
+}
+
+// Expired reports whether the session has outlived its TTL as of now.
+func (s Session) Expired(now time.Time) bool {
+	if s.TTL <= 0 {
+		return false
+	}
+	return now.After(s.CreatedAt.Add(s.TTL))
+}
+
+// AcknowledgedParts returns the part numbers recorded so far, so a client
+// resuming an upload knows which ones it can skip.
+func (s Session) AcknowledgedParts() []int32 {
+	parts := make([]int32, 0, len(s.Parts))
+	for partNumber := range s.Parts {
+		parts = append(parts, partNumber)
+	}
+	return parts
+}
+
+// Store persists Sessions. Implementations must be safe for concurrent
+// use.
+type Store interface {
+	Create(session *Session) error
+	Get(uploadID string) (*Session, bool, error)
+	RecordPart(uploadID string, partNumber int32, etag string) error
+	Delete(uploadID string) error
+	// ListExpired returns every session whose TTL has elapsed as of now,
+	// for the janitor to abort and clean up.
+	ListExpired(now time.Time) ([]*Session, error)
+
+	// CreateShareToken persists a one-time/rate-limited download token
+	// minted by GenerateShareUrl.
+	CreateShareToken(token *ShareToken) error
+	GetShareToken(token string) (*ShareToken, bool, error)
+	// IncrementShareTokenDownloads records one more completed download
+	// against token, for enforcing MaxDownloads.
+	IncrementShareTokenDownloads(token string) error
+	DeleteShareToken(token string) error
+}
+
+// ShareToken is an opaque, short-lived stand-in for a presigned URL, used
+// when a download needs to be revocable or rate-limited in ways a raw S3
+// presigned URL can't enforce (a download counter, an IP allow-list).
+type ShareToken struct {
+	Token        string
+	CredentialID string
+	Key          string
+	ExpiresAt    time.Time
+	// MaxDownloads <= 0 means unlimited.
+	MaxDownloads  int
+	DownloadCount int
+	// IPAllowList, when non-empty, restricts downloads to these client
+	// IPs. Empty means any client may use the token.
+	IPAllowList         []string
+	ContentDisposition  string
+	ResponseContentType string
+	// RangeStart/RangeEnd pin the token to a fixed byte range (both zero
+	// means the whole object), so a share link can point at e.g. just the
+	// first page of a large file.
+	RangeStart int64
+	RangeEnd   int64
+}
+
+// Expired reports whether the token has outlived its expiry as of now.
+func (t ShareToken) Expired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && now.After(t.ExpiresAt)
+}
+
+// Exhausted reports whether the token has hit its download limit.
+func (t ShareToken) Exhausted() bool {
+	return t.MaxDownloads > 0 && t.DownloadCount >= t.MaxDownloads
+}
+
+// IPAllowed reports whether clientIP may use this token.
+func (t ShareToken) IPAllowed(clientIP string) bool {
+	if len(t.IPAllowList) == 0 {
+		return true
+	}
+	for _, allowed := range t.IPAllowList {
+		if allowed == clientIP {
+			return true
+		}
+	}
+	return false
+}
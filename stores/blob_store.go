@@ -0,0 +1,244 @@
+package stores
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-aws/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"gocloud.dev/blob"
+)
+
+// BlobStore adapts any gocloud.dev/blob driver (S3, GCS, Azure Blob, local
+// filesystem, ...) to interfaces.ObjectStore, for backends that don't
+// warrant (or don't have) a dedicated native implementation.
+//
+// gocloud.dev/blob has no concept of server-side multipart upload, so a
+// "multipart" upload here is really a single blob.Writer kept open in
+// memory between CreateMultipartUpload and CompleteMultipartUpload; parts
+// must arrive in order. Presigning is driver-dependent (not every blob
+// backend can sign URLs), so SupportsPresign() is false and callers
+// should prefer the streaming proxy path for this backend.
+type BlobStore struct {
+	bucket *blob.Bucket
+
+	mu      sync.Mutex
+	writers map[string]*blobUpload
+}
+
+type blobUpload struct {
+	key       string
+	writer    *blob.Writer
+	initiated time.Time
+}
+
+// NewBlobStore wraps an already-opened gocloud.dev/blob bucket (e.g. via
+// blob.OpenBucket with an "s3://", "gs://", "azblob://" or "file://" URL).
+func NewBlobStore(bucket *blob.Bucket) *BlobStore {
+	return &BlobStore{
+		bucket:  bucket,
+		writers: map[string]*blobUpload{},
+	}
+}
+
+func (st *BlobStore) CreateMultipartUpload(ctx context.Context, key string) (string, error) {
+	writer, err := st.bucket.NewWriter(ctx, key, nil)
+	if err != nil {
+		return "", err
+	}
+
+	initiated := time.Now().UTC()
+	uploadID := key + "@" + initiated.Format(time.RFC3339Nano)
+	st.mu.Lock()
+	st.writers[uploadID] = &blobUpload{key: key, writer: writer, initiated: initiated}
+	st.mu.Unlock()
+
+	return uploadID, nil
+}
+
+func (st *BlobStore) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.Reader) (string, error) {
+	st.mu.Lock()
+	upload, ok := st.writers[uploadID]
+	st.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("unknown blob upload id %q", uploadID)
+	}
+
+	if _, err := io.Copy(upload.writer, body); err != nil {
+		return "", err
+	}
+	// gocloud.dev/blob has no per-part ETag; the part number stands in
+	// for one. CompleteMultipartUpload ignores the parts list, since the
+	// bytes were already appended to the writer in arrival order.
+	return fmt.Sprintf("part-%d", partNumber), nil
+}
+
+func (st *BlobStore) PresignUploadPart(ctx context.Context, key, uploadID string, partNumber int32, expires time.Duration) (string, error) {
+	return "", fmt.Errorf("blob backend does not support presigned part uploads; use the streaming upload path")
+}
+
+func (st *BlobStore) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []types.CompletedPart) (string, error) {
+	st.mu.Lock()
+	upload, ok := st.writers[uploadID]
+	delete(st.writers, uploadID)
+	st.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("unknown blob upload id %q", uploadID)
+	}
+
+	if err := upload.writer.Close(); err != nil {
+		return "", err
+	}
+
+	attrs, err := st.bucket.Attributes(ctx, upload.key)
+	if err != nil {
+		return "", err
+	}
+	return attrs.ETag, nil
+}
+
+func (st *BlobStore) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	st.mu.Lock()
+	upload, ok := st.writers[uploadID]
+	delete(st.writers, uploadID)
+	st.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	upload.writer.Close()
+	return st.bucket.Delete(ctx, upload.key)
+}
+
+// ListMultipartUploads reports the in-memory blobUploads this process
+// currently has open. Since gocloud.dev/blob has no server-side notion of
+// an in-progress multipart upload, this only sees uploads started on this
+// process; it cannot discover ones left behind by a crashed instance.
+func (st *BlobStore) ListMultipartUploads(ctx context.Context) ([]interfaces.MultipartUploadInfo, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	uploads := make([]interfaces.MultipartUploadInfo, 0, len(st.writers))
+	for uploadID, upload := range st.writers {
+		uploads = append(uploads, interfaces.MultipartUploadInfo{
+			Key:       upload.key,
+			UploadID:  uploadID,
+			Initiated: upload.initiated,
+		})
+	}
+	return uploads, nil
+}
+
+func (st *BlobStore) List(ctx context.Context, prefix, delimiter string) ([]types.Object, error) {
+	iter := st.bucket.List(&blob.ListOptions{Prefix: prefix, Delimiter: delimiter})
+
+	var objects []types.Object
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, types.Object{Key: aws.String(obj.Key), Size: aws.Int64(obj.Size)})
+	}
+	return objects, nil
+}
+
+func (st *BlobStore) Delete(ctx context.Context, key string) error {
+	return st.bucket.Delete(ctx, key)
+}
+
+func (st *BlobStore) Copy(ctx context.Context, sourceKey, destinationKey string) error {
+	return st.bucket.Copy(ctx, destinationKey, sourceKey, nil)
+}
+
+func (st *BlobStore) Head(ctx context.Context, key string) (*s3.HeadObjectOutput, error) {
+	attrs, err := st.bucket.Attributes(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &s3.HeadObjectOutput{
+		ContentLength: aws.Int64(attrs.Size),
+		ETag:          aws.String(attrs.ETag),
+	}, nil
+}
+
+func (st *BlobStore) Get(ctx context.Context, key, rangeHeader string) (*interfaces.ObjectRange, error) {
+	var offset, length int64 = 0, -1
+	if rangeHeader != "" {
+		start, end, err := parseByteRange(rangeHeader)
+		if err != nil {
+			return nil, err
+		}
+		offset = start
+		if end >= 0 {
+			length = end - start + 1
+		}
+	}
+
+	reader, err := st.bucket.NewRangeReader(ctx, key, offset, length, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	totalSize := reader.Size()
+	servedLength := length
+	if servedLength < 0 {
+		servedLength = totalSize - offset
+	}
+
+	objRange := &interfaces.ObjectRange{
+		Body:          reader,
+		ContentLength: servedLength,
+		ContentType:   reader.ContentType(),
+	}
+	if rangeHeader != "" {
+		objRange.ContentRange = fmt.Sprintf("bytes %d-%d/%d", offset, offset+servedLength-1, totalSize)
+	}
+	return objRange, nil
+}
+
+// parseByteRange parses a single-range HTTP Range header value (e.g.
+// "bytes=0-1023") into inclusive start/end offsets. end is -1 when the
+// range is open-ended ("bytes=0-").
+func parseByteRange(rangeHeader string) (start, end int64, err error) {
+	spec := strings.TrimPrefix(rangeHeader, "bytes=")
+	parts := strings.SplitN(spec, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("malformed range header %q", rangeHeader)
+	}
+	if parts[0] == "" {
+		return 0, 0, fmt.Errorf("suffix ranges are not supported")
+	}
+	start, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed range start: %w", err)
+	}
+	if parts[1] == "" {
+		return start, -1, nil
+	}
+	end, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed range end: %w", err)
+	}
+	return start, end, nil
+}
+
+func (st *BlobStore) PresignGet(ctx context.Context, key string, expires time.Duration, responseContentDisposition string) (string, error) {
+	return "", fmt.Errorf("blob backend does not support presigned downloads; use the streaming download path")
+}
+
+func (st *BlobStore) SupportsPresign() bool {
+	return false
+}
+
+var _ interfaces.ObjectStore = (*BlobStore)(nil)
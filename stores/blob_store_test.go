@@ -0,0 +1,35 @@
+package stores
+
+import "testing"
+
+func TestParseByteRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		wantStart int64
+		wantEnd   int64
+		wantErr   bool
+	}{
+		{name: "closed range", header: "bytes=0-1023", wantStart: 0, wantEnd: 1023},
+		{name: "open-ended range", header: "bytes=1000-", wantStart: 1000, wantEnd: -1},
+		{name: "missing dash", header: "bytes=1000", wantErr: true},
+		{name: "suffix range unsupported", header: "bytes=-500", wantErr: true},
+		{name: "malformed start", header: "bytes=abc-500", wantErr: true},
+		{name: "malformed end", header: "bytes=0-abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := parseByteRange(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseByteRange(%q) error = %v, wantErr %v", tt.header, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("parseByteRange(%q) = (%d, %d), want (%d, %d)", tt.header, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package stores
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go-aws/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	cos "github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// COSStore talks to Tencent Cloud Object Storage. COS's XML API is close
+// enough to S3's that cos-go-sdk-v5 exposes the same multipart and
+// presigning primitives, so this backend keeps SupportsPresign() == true.
+type COSStore struct {
+	client *cos.Client
+}
+
+// NewCOSStore builds a COSStore for the given bucket URL (e.g.
+// https://<bucket>-<appid>.cos.<region>.myqcloud.com) and secret pair.
+func NewCOSStore(bucketURL, secretID, secretKey string) (*COSStore, error) {
+	u, err := url.Parse(bucketURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing COS bucket url: %w", err)
+	}
+
+	client := cos.NewClient(&cos.BaseURL{BucketURL: u}, &http.Client{
+		Transport: &cos.AuthorizationTransport{SecretID: secretID, SecretKey: secretKey},
+	})
+
+	return &COSStore{client: client}, nil
+}
+
+func (st *COSStore) CreateMultipartUpload(ctx context.Context, key string) (string, error) {
+	result, _, err := st.client.Object.InitiateMultipartUpload(ctx, key, nil)
+	if err != nil {
+		return "", err
+	}
+	return result.UploadID, nil
+}
+
+func (st *COSStore) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.Reader) (string, error) {
+	resp, err := st.client.Object.UploadPart(ctx, key, uploadID, int(partNumber), body, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+func (st *COSStore) PresignUploadPart(ctx context.Context, key, uploadID string, partNumber int32, expires time.Duration) (string, error) {
+	u, err := st.client.Object.GetPresignedURL(ctx, http.MethodPut, key, "", "", expires, map[string]string{
+		"uploadId":   uploadID,
+		"partNumber": fmt.Sprintf("%d", partNumber),
+	})
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (st *COSStore) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []types.CompletedPart) (string, error) {
+	opt := &cos.CompleteMultipartUploadOptions{}
+	for _, part := range parts {
+		opt.Parts = append(opt.Parts, cos.Object{
+			PartNumber: int(*part.PartNumber),
+			ETag:       *part.ETag,
+		})
+	}
+	result, _, err := st.client.Object.CompleteMultipartUpload(ctx, key, uploadID, opt)
+	if err != nil {
+		return "", err
+	}
+	return result.ETag, nil
+}
+
+func (st *COSStore) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	_, err := st.client.Object.AbortMultipartUpload(ctx, key, uploadID)
+	return err
+}
+
+func (st *COSStore) ListMultipartUploads(ctx context.Context) ([]interfaces.MultipartUploadInfo, error) {
+	result, _, err := st.client.Bucket.ListMultipartUploads(ctx, &cos.ListMultipartUploadsOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	uploads := make([]interfaces.MultipartUploadInfo, 0, len(result.Uploads))
+	for _, upload := range result.Uploads {
+		initiated, _ := time.Parse(time.RFC3339, upload.Initiated)
+		uploads = append(uploads, interfaces.MultipartUploadInfo{
+			Key:       upload.Key,
+			UploadID:  upload.UploadID,
+			Initiated: initiated,
+		})
+	}
+	return uploads, nil
+}
+
+func (st *COSStore) List(ctx context.Context, prefix, delimiter string) ([]types.Object, error) {
+	result, _, err := st.client.Bucket.Get(ctx, &cos.BucketGetOptions{
+		Prefix:    prefix,
+		Delimiter: delimiter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]types.Object, 0, len(result.Contents))
+	for _, obj := range result.Contents {
+		objects = append(objects, types.Object{Key: aws.String(obj.Key), Size: aws.Int64(obj.Size)})
+	}
+	return objects, nil
+}
+
+func (st *COSStore) Delete(ctx context.Context, key string) error {
+	_, err := st.client.Object.Delete(ctx, key)
+	return err
+}
+
+func (st *COSStore) Copy(ctx context.Context, sourceKey, destinationKey string) error {
+	_, _, err := st.client.Object.Copy(ctx, destinationKey, st.client.BaseURL.BucketURL.Host+"/"+sourceKey, nil)
+	return err
+}
+
+func (st *COSStore) Head(ctx context.Context, key string) (*s3.HeadObjectOutput, error) {
+	resp, err := st.client.Object.Head(ctx, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &s3.HeadObjectOutput{
+		ContentLength: aws.Int64(resp.ContentLength),
+		ETag:          aws.String(resp.Header.Get("ETag")),
+	}, nil
+}
+
+func (st *COSStore) Get(ctx context.Context, key, rangeHeader string) (*interfaces.ObjectRange, error) {
+	opt := &cos.ObjectGetOptions{}
+	if rangeHeader != "" {
+		opt.Range = rangeHeader
+	}
+	resp, err := st.client.Object.Get(ctx, key, opt)
+	if err != nil {
+		return nil, err
+	}
+	return &interfaces.ObjectRange{
+		Body:          resp.Body,
+		ContentLength: resp.ContentLength,
+		ContentRange:  resp.Header.Get("Content-Range"),
+		ContentType:   resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+func (st *COSStore) PresignGet(ctx context.Context, key string, expires time.Duration, responseContentDisposition string) (string, error) {
+	query := url.Values{}
+	if responseContentDisposition != "" {
+		query.Set("response-content-disposition", responseContentDisposition)
+	}
+	u, err := st.client.Object.GetPresignedURL(ctx, http.MethodGet, key, "", "", expires, query)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (st *COSStore) SupportsPresign() bool {
+	return true
+}
+
+var _ interfaces.ObjectStore = (*COSStore)(nil)
@@ -0,0 +1,45 @@
+package stores
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"go-aws/interfaces"
+	"go-aws/utils"
+
+	"gocloud.dev/blob"
+)
+
+// New builds the interfaces.ObjectStore for the given backend name and
+// credential. Route handlers never construct a backend directly; they go
+// through a cached factory call in controllers.S3Properties instead.
+func New(ctx context.Context, backend string, cred utils.S3Credential) (interfaces.ObjectStore, error) {
+	switch backend {
+	case "", "s3":
+		return NewS3Store(cred.AccessKey, cred.SecretKey, cred.Region, cred.Endpoint, cred.BucketName, cred.ACL), nil
+	case "minio":
+		// cred.Endpoint is validated as a full scheme://host[:port] URL like
+		// every other backend, but minio.NewCore wants a bare host[:port]
+		// and derives the scheme itself from Secure, so split the two here.
+		endpoint, err := url.Parse(cred.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("parsing minio endpoint: %w", err)
+		}
+		return NewMinIOStore(endpoint.Host, cred.AccessKey, cred.SecretKey, cred.BucketName, endpoint.Scheme == "https")
+	case "cos":
+		return NewCOSStore(cred.Endpoint, cred.AccessKey, cred.SecretKey)
+	case "oss":
+		return NewOSSStore(cred.Endpoint, cred.AccessKey, cred.SecretKey, cred.BucketName)
+	case "blob":
+		// cred.Endpoint is a gocloud.dev/blob bucket URL here, e.g.
+		// "s3://my-bucket", "gs://my-bucket" or "file:///data/buckets/my-bucket".
+		bucket, err := blob.OpenBucket(ctx, cred.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("opening blob bucket: %w", err)
+		}
+		return NewBlobStore(bucket), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", backend)
+	}
+}
@@ -0,0 +1,172 @@
+package stores
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"go-aws/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// MinIOStore talks to a MinIO (or MinIO-compatible) endpoint through the
+// native minio-go SDK rather than the AWS SDK, since minio-go exposes
+// MinIO-specific multipart primitives (minio.Core) the AWS SDK doesn't.
+//
+// minio-go's Core client has no per-part presigning, so this backend
+// reports SupportsPresign() == false; uploads to it go through the
+// streaming proxy path instead of client-side presigned PUTs.
+type MinIOStore struct {
+	core       *minio.Core
+	bucketName string
+}
+
+// NewMinIOStore builds a MinIOStore for the given endpoint and static
+// credentials. useSSL controls whether the client talks https or http to
+// the endpoint, which is commonly plain http for in-cluster MinIO.
+func NewMinIOStore(endpoint, accessKey, secretKey, bucketName string, useSSL bool) (*MinIOStore, error) {
+	core, err := minio.NewCore(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating minio client: %w", err)
+	}
+
+	return &MinIOStore{core: core, bucketName: bucketName}, nil
+}
+
+func (st *MinIOStore) CreateMultipartUpload(ctx context.Context, key string) (string, error) {
+	return st.core.NewMultipartUpload(ctx, st.bucketName, key, minio.PutObjectOptions{})
+}
+
+func (st *MinIOStore) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.Reader) (string, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	part, err := st.core.PutObjectPart(ctx, st.bucketName, key, uploadID, int(partNumber), bytes.NewReader(data), int64(len(data)), minio.PutObjectPartOptions{})
+	if err != nil {
+		return "", err
+	}
+	return part.ETag, nil
+}
+
+func (st *MinIOStore) PresignUploadPart(ctx context.Context, key, uploadID string, partNumber int32, expires time.Duration) (string, error) {
+	return "", fmt.Errorf("minio backend does not support presigned part uploads; use the streaming upload path")
+}
+
+func (st *MinIOStore) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []types.CompletedPart) (string, error) {
+	completed := make([]minio.CompletePart, len(parts))
+	for i, part := range parts {
+		completed[i] = minio.CompletePart{
+			PartNumber: int(*part.PartNumber),
+			ETag:       *part.ETag,
+		}
+	}
+	result, err := st.core.CompleteMultipartUpload(ctx, st.bucketName, key, uploadID, completed, minio.PutObjectOptions{})
+	if err != nil {
+		return "", err
+	}
+	return result.ETag, nil
+}
+
+func (st *MinIOStore) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	return st.core.AbortMultipartUpload(ctx, st.bucketName, key, uploadID)
+}
+
+func (st *MinIOStore) ListMultipartUploads(ctx context.Context) ([]interfaces.MultipartUploadInfo, error) {
+	result, err := st.core.ListMultipartUploads(ctx, st.bucketName, "", "", "", "", 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	uploads := make([]interfaces.MultipartUploadInfo, 0, len(result.Uploads))
+	for _, upload := range result.Uploads {
+		uploads = append(uploads, interfaces.MultipartUploadInfo{
+			Key:       upload.Key,
+			UploadID:  upload.UploadID,
+			Initiated: upload.Initiated,
+		})
+	}
+	return uploads, nil
+}
+
+func (st *MinIOStore) List(ctx context.Context, prefix, delimiter string) ([]types.Object, error) {
+	result, err := st.core.ListObjectsV2(st.bucketName, prefix, "", "", delimiter, 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]types.Object, 0, len(result.Contents))
+	for _, obj := range result.Contents {
+		objects = append(objects, types.Object{Key: aws.String(obj.Key), Size: aws.Int64(obj.Size)})
+	}
+	return objects, nil
+}
+
+func (st *MinIOStore) Delete(ctx context.Context, key string) error {
+	return st.core.Client.RemoveObject(ctx, st.bucketName, key, minio.RemoveObjectOptions{})
+}
+
+func (st *MinIOStore) Copy(ctx context.Context, sourceKey, destinationKey string) error {
+	_, err := st.core.Client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: st.bucketName, Object: destinationKey},
+		minio.CopySrcOptions{Bucket: st.bucketName, Object: sourceKey},
+	)
+	return err
+}
+
+func (st *MinIOStore) Head(ctx context.Context, key string) (*s3.HeadObjectOutput, error) {
+	info, err := st.core.Client.StatObject(ctx, st.bucketName, key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &s3.HeadObjectOutput{ContentLength: aws.Int64(info.Size), ETag: aws.String(info.ETag)}, nil
+}
+
+func (st *MinIOStore) Get(ctx context.Context, key, rangeHeader string) (*interfaces.ObjectRange, error) {
+	opts := minio.GetObjectOptions{}
+	if rangeHeader != "" {
+		opts.Set("Range", rangeHeader)
+	}
+	obj, err := st.core.Client.GetObject(ctx, st.bucketName, key, opts)
+	if err != nil {
+		return nil, err
+	}
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, err
+	}
+	return &interfaces.ObjectRange{
+		Body:          obj,
+		ContentLength: info.Size,
+		ContentType:   info.ContentType,
+	}, nil
+}
+
+func (st *MinIOStore) PresignGet(ctx context.Context, key string, expires time.Duration, responseContentDisposition string) (string, error) {
+	reqParams := make(map[string][]string)
+	if responseContentDisposition != "" {
+		reqParams["response-content-disposition"] = []string{responseContentDisposition}
+	}
+	url, err := st.core.Client.PresignedGetObject(ctx, st.bucketName, key, expires, reqParams)
+	if err != nil {
+		return "", err
+	}
+	return url.String(), nil
+}
+
+func (st *MinIOStore) SupportsPresign() bool {
+	return false
+}
+
+var _ interfaces.ObjectStore = (*MinIOStore)(nil)
@@ -0,0 +1,225 @@
+// Package stores holds one interfaces.ObjectStore implementation per
+// supported backend, selected at startup via utils.Config.StorageBackend.
+package stores
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"go-aws/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Store is the default interfaces.ObjectStore backend, talking to AWS S3
+// (or any endpoint that speaks its API, e.g. when fronted by a compatible
+// gateway) via the official SDK.
+type S3Store struct {
+	client     *s3.Client
+	presign    *s3.PresignClient
+	bucketName string
+	acl        types.ObjectCannedACL
+}
+
+// NewS3Store builds an S3Store for the given bucket, region, endpoint and
+// static credentials, using path-style addressing so self-hosted
+// endpoints (MinIO-as-S3, Wasabi, ...) work without a wildcard DNS setup.
+func NewS3Store(accessKey, secretKey, region, endpoint, bucketName, acl string) *S3Store {
+	client := s3.New(s3.Options{
+		Region:       region,
+		BaseEndpoint: &endpoint,
+		UsePathStyle: true,
+		Credentials: aws.NewCredentialsCache(
+			credentials.NewStaticCredentialsProvider(accessKey, secretKey, ""),
+		),
+	})
+
+	return &S3Store{
+		client:     client,
+		presign:    s3.NewPresignClient(client),
+		bucketName: bucketName,
+		acl:        types.ObjectCannedACL(acl),
+	}
+}
+
+func (st *S3Store) CreateMultipartUpload(ctx context.Context, key string) (string, error) {
+	params := &s3.CreateMultipartUploadInput{
+		Bucket: &st.bucketName,
+		Key:    &key,
+	}
+	if st.acl != "" {
+		params.ACL = st.acl
+	}
+	resp, err := st.client.CreateMultipartUpload(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	return *resp.UploadId, nil
+}
+
+func (st *S3Store) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.Reader) (string, error) {
+	resp, err := st.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     &st.bucketName,
+		Key:        &key,
+		UploadId:   &uploadID,
+		PartNumber: &partNumber,
+		Body:       body,
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(resp.ETag), nil
+}
+
+func (st *S3Store) PresignUploadPart(ctx context.Context, key, uploadID string, partNumber int32, expires time.Duration) (string, error) {
+	resp, err := st.presign.PresignUploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     &st.bucketName,
+		Key:        &key,
+		UploadId:   &uploadID,
+		PartNumber: &partNumber,
+	}, func(opts *s3.PresignOptions) {
+		opts.Expires = expires
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.URL, nil
+}
+
+func (st *S3Store) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []types.CompletedPart) (string, error) {
+	resp, err := st.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   &st.bucketName,
+		Key:      &key,
+		UploadId: &uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: parts,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(resp.ETag), nil
+}
+
+func (st *S3Store) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	_, err := st.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   &st.bucketName,
+		Key:      &key,
+		UploadId: &uploadID,
+	})
+	return err
+}
+
+func (st *S3Store) ListMultipartUploads(ctx context.Context) ([]interfaces.MultipartUploadInfo, error) {
+	resp, err := st.client.ListMultipartUploads(ctx, &s3.ListMultipartUploadsInput{
+		Bucket: &st.bucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uploads := make([]interfaces.MultipartUploadInfo, 0, len(resp.Uploads))
+	for _, upload := range resp.Uploads {
+		info := interfaces.MultipartUploadInfo{
+			Key:      aws.ToString(upload.Key),
+			UploadID: aws.ToString(upload.UploadId),
+		}
+		if upload.Initiated != nil {
+			info.Initiated = *upload.Initiated
+		}
+		uploads = append(uploads, info)
+	}
+	return uploads, nil
+}
+
+func (st *S3Store) List(ctx context.Context, prefix, delimiter string) ([]types.Object, error) {
+	resp, err := st.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket:    &st.bucketName,
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String(delimiter),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Contents, nil
+}
+
+func (st *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := st.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &st.bucketName,
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (st *S3Store) Copy(ctx context.Context, sourceKey, destinationKey string) error {
+	params := &s3.CopyObjectInput{
+		Bucket:     &st.bucketName,
+		CopySource: aws.String(st.bucketName + "/" + sourceKey),
+		Key:        aws.String(destinationKey),
+	}
+	if st.acl != "" {
+		params.ACL = st.acl
+	}
+	_, err := st.client.CopyObject(ctx, params)
+	return err
+}
+
+func (st *S3Store) Head(ctx context.Context, key string) (*s3.HeadObjectOutput, error) {
+	return st.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: &st.bucketName,
+		Key:    &key,
+		// ChecksumModeEnabled surfaces the object's ChecksumCRC32C/
+		// ChecksumSHA256 fields (when it was uploaded with one recorded),
+		// which the share-link downloader passes through as a Digest
+		// header.
+		ChecksumMode: types.ChecksumModeEnabled,
+	})
+}
+
+func (st *S3Store) Get(ctx context.Context, key, rangeHeader string) (*interfaces.ObjectRange, error) {
+	params := &s3.GetObjectInput{
+		Bucket: &st.bucketName,
+		Key:    aws.String(key),
+	}
+	if rangeHeader != "" {
+		params.Range = aws.String(rangeHeader)
+	}
+	resp, err := st.client.GetObject(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &interfaces.ObjectRange{
+		Body:          resp.Body,
+		ContentLength: aws.ToInt64(resp.ContentLength),
+		ContentRange:  aws.ToString(resp.ContentRange),
+		ContentType:   aws.ToString(resp.ContentType),
+	}, nil
+}
+
+func (st *S3Store) PresignGet(ctx context.Context, key string, expires time.Duration, responseContentDisposition string) (string, error) {
+	params := &s3.GetObjectInput{
+		Bucket: &st.bucketName,
+		Key:    aws.String(key),
+	}
+	if responseContentDisposition != "" {
+		params.ResponseContentDisposition = aws.String(responseContentDisposition)
+	}
+	resp, err := st.presign.PresignGetObject(ctx, params, func(opts *s3.PresignOptions) {
+		opts.Expires = expires
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.URL, nil
+}
+
+func (st *S3Store) SupportsPresign() bool {
+	return true
+}
+
+var _ interfaces.ObjectStore = (*S3Store)(nil)
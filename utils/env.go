@@ -1,12 +1,54 @@
 package utils
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
+// S3Credential describes a single named set of S3-compatible credentials.
+// A server can hold many of these at once so that one deployment can front
+// several buckets, regions or even providers (AWS, MinIO, Wasabi, ...)
+// simultaneously, keyed by ID.
+type S3Credential struct {
+	ID         string `json:"id"`
+	AccessKey  string `json:"accessKey"`
+	SecretKey  string `json:"secretKey"`
+	Region     string `json:"region"`
+	Endpoint   string `json:"endpoint"`
+	BucketName string `json:"bucketName"`
+	// Prefix is prepended to every key this credential touches, so that
+	// multiple tenants can safely share a single bucket.
+	Prefix string `json:"prefix,omitempty"`
+	// ACL is the canned ACL (if any) applied to objects uploaded with
+	// this credential, e.g. "private" or "public-read".
+	ACL string `json:"acl,omitempty"`
+}
+
+// Validate checks that the credential is well-formed enough to build an S3
+// client from and safely prefix keys with.
+func (c S3Credential) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("credential id is required")
+	}
+	if c.BucketName == "" {
+		return fmt.Errorf("credential %q: bucketName is required", c.ID)
+	}
+	if strings.HasSuffix(c.Endpoint, "/") {
+		return fmt.Errorf("credential %q: endpoint must not end with \"/\"", c.ID)
+	}
+	if strings.HasPrefix(c.Prefix, "/") {
+		return fmt.Errorf("credential %q: prefix must not start with \"/\"", c.ID)
+	}
+	return nil
+}
+
 // Config holds the application's configuration values.
 type Config struct {
 	CORSOrigin     string
@@ -19,6 +61,42 @@ type Config struct {
 	S3Endpoint     string
 	S3SecretKey    string
 	S3Region       string
+	// CredentialsFile, when set, points at a JSON file holding a list of
+	// S3Credential entries that seed the multi-tenant credential registry.
+	CredentialsFile string
+	// S3Credentials is the registry loaded from CredentialsFile, keyed by
+	// S3Credential.ID. Additional entries can be registered at runtime
+	// through the /credentials admin API.
+	S3Credentials map[string]S3Credential
+	// StorageBackend selects which interfaces.ObjectStore implementation
+	// backs every credential: "s3" (default), "minio", "cos", "oss" or
+	// "blob" (gocloud.dev/blob).
+	StorageBackend string
+	// SessionStoreBackend selects which sessions.Store implementation
+	// tracks in-flight multipart uploads: "memory" (default), "bolt" or
+	// "redis".
+	SessionStoreBackend string
+	// SessionStoreDSN is the BoltDB file path for "bolt" or the
+	// "host:port" address for "redis"; unused for "memory".
+	SessionStoreDSN string
+	// UploadSessionTTL is how long an upload session may sit without
+	// completing before the janitor aborts it and reclaims the orphaned
+	// multipart upload.
+	UploadSessionTTL time.Duration
+	// JanitorInterval is how often the janitor sweeps for expired upload
+	// sessions.
+	JanitorInterval time.Duration
+	// WebhookURLs are callback URLs notified on every object change, in
+	// addition to any registered later through POST /webhooks/subscribe.
+	WebhookURLs []string
+	// WebhookSecret signs the HMAC-SHA256 included with every webhook
+	// delivery so receivers can verify it came from this server.
+	WebhookSecret string
+	// AdminToken gates the credential-registry and webhook-subscription
+	// admin endpoints. Requests must send it as "X-Admin-Token"; if it's
+	// empty those endpoints refuse every request rather than default to
+	// open.
+	AdminToken string
 }
 
 // LoadConfig initializes and returns the configuration using environment variables.
@@ -38,6 +116,15 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("S3_ENDPOINT", "")
 	viper.SetDefault("S3_SECRET_ACCESS_KEY", "")
 	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("S3_CREDENTIALS_FILE", "")
+	viper.SetDefault("STORAGE_BACKEND", "s3")
+	viper.SetDefault("SESSION_STORE_BACKEND", "memory")
+	viper.SetDefault("SESSION_STORE_DSN", "")
+	viper.SetDefault("UPLOAD_SESSION_TTL", "24h")
+	viper.SetDefault("JANITOR_INTERVAL", "15m")
+	viper.SetDefault("WEBHOOK_URLS", "")
+	viper.SetDefault("WEBHOOK_SECRET", "")
+	viper.SetDefault("ADMIN_TOKEN", "")
 
 	// Bind environment variables
 	viper.BindEnv("CORS_ORIGIN")
@@ -49,22 +136,41 @@ func LoadConfig() (*Config, error) {
 	viper.BindEnv("S3_ENDPOINT")
 	viper.BindEnv("S3_SECRET_ACCESS_KEY")
 	viper.BindEnv("S3_REGION")
+	viper.BindEnv("S3_CREDENTIALS_FILE")
+	viper.BindEnv("STORAGE_BACKEND")
+	viper.BindEnv("SESSION_STORE_BACKEND")
+	viper.BindEnv("SESSION_STORE_DSN")
+	viper.BindEnv("UPLOAD_SESSION_TTL")
+	viper.BindEnv("JANITOR_INTERVAL")
+	viper.BindEnv("WEBHOOK_URLS")
+	viper.BindEnv("WEBHOOK_SECRET")
+	viper.BindEnv("ADMIN_TOKEN")
 
 	// Read environment variables
 	viper.AutomaticEnv()
 
 	// Initialize config values from environment or defaults
 	config := &Config{
-		CORSOrigin:     viper.GetString("CORS_ORIGIN"),
-		Environment:    viper.GetString("NODE_ENV"),
-		HTTPPort:       viper.GetInt("PORT"),
-		HTTPHost:       viper.GetString("HOST"),
-		NgrokAuthToken: viper.GetString("NGROK_AUTHTOKEN"),
-		S3AccessKey:    viper.GetString("S3_ACCESS_KEY_ID"),
-		S3BucketName:   viper.GetString("S3_BUCKET_NAME"),
-		S3Endpoint:     viper.GetString("S3_ENDPOINT"),
-		S3SecretKey:    viper.GetString("S3_SECRET_ACCESS_KEY"),
-		S3Region:       viper.GetString("S3_REGION"),
+		CORSOrigin:          viper.GetString("CORS_ORIGIN"),
+		Environment:         viper.GetString("NODE_ENV"),
+		HTTPPort:            viper.GetInt("PORT"),
+		HTTPHost:            viper.GetString("HOST"),
+		NgrokAuthToken:      viper.GetString("NGROK_AUTHTOKEN"),
+		S3AccessKey:         viper.GetString("S3_ACCESS_KEY_ID"),
+		S3BucketName:        viper.GetString("S3_BUCKET_NAME"),
+		S3Endpoint:          viper.GetString("S3_ENDPOINT"),
+		S3SecretKey:         viper.GetString("S3_SECRET_ACCESS_KEY"),
+		S3Region:            viper.GetString("S3_REGION"),
+		CredentialsFile:     viper.GetString("S3_CREDENTIALS_FILE"),
+		S3Credentials:       map[string]S3Credential{},
+		StorageBackend:      viper.GetString("STORAGE_BACKEND"),
+		SessionStoreBackend: viper.GetString("SESSION_STORE_BACKEND"),
+		SessionStoreDSN:     viper.GetString("SESSION_STORE_DSN"),
+		UploadSessionTTL:    viper.GetDuration("UPLOAD_SESSION_TTL"),
+		JanitorInterval:     viper.GetDuration("JANITOR_INTERVAL"),
+		WebhookURLs:         splitAndTrim(viper.GetString("WEBHOOK_URLS"), ","),
+		WebhookSecret:       viper.GetString("WEBHOOK_SECRET"),
+		AdminToken:          viper.GetString("ADMIN_TOKEN"),
 	}
 
 	// Validate required fields (example: checking if S3 keys are set)
@@ -72,5 +178,48 @@ func LoadConfig() (*Config, error) {
 		log.Println("Warning: Some required S3 configurations are missing.")
 	}
 
+	if config.CredentialsFile != "" {
+		if err := config.loadCredentialsFile(); err != nil {
+			return nil, err
+		}
+	}
+
 	return config, nil
 }
+
+// splitAndTrim splits s on sep and drops empty/whitespace-only entries, so
+// an unset or trailing-comma env var yields an empty slice instead of a
+// slice containing "".
+func splitAndTrim(s, sep string) []string {
+	var out []string
+	for _, part := range strings.Split(s, sep) {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
+// loadCredentialsFile reads CredentialsFile and populates S3Credentials,
+// keyed by each entry's ID. Entries that fail validation are rejected
+// outright so a bad config file never reaches request handlers.
+func (c *Config) loadCredentialsFile() error {
+	data, err := os.ReadFile(c.CredentialsFile)
+	if err != nil {
+		return fmt.Errorf("reading credentials file: %w", err)
+	}
+
+	var entries []S3Credential
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("parsing credentials file: %w", err)
+	}
+
+	for _, entry := range entries {
+		if err := entry.Validate(); err != nil {
+			return fmt.Errorf("invalid entry in credentials file: %w", err)
+		}
+		c.S3Credentials[entry.ID] = entry
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package utils
+
+import "testing"
+
+func TestS3CredentialValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cred    S3Credential
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			cred:    S3Credential{ID: "default", BucketName: "bucket", Endpoint: "https://s3.example.com"},
+			wantErr: false,
+		},
+		{
+			name:    "missing id",
+			cred:    S3Credential{BucketName: "bucket"},
+			wantErr: true,
+		},
+		{
+			name:    "missing bucket name",
+			cred:    S3Credential{ID: "default"},
+			wantErr: true,
+		},
+		{
+			name:    "endpoint with trailing slash",
+			cred:    S3Credential{ID: "default", BucketName: "bucket", Endpoint: "https://s3.example.com/"},
+			wantErr: true,
+		},
+		{
+			name:    "prefix with leading slash",
+			cred:    S3Credential{ID: "default", BucketName: "bucket", Prefix: "/tenant"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cred.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
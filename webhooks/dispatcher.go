@@ -0,0 +1,134 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	maxAttempts = 5
+	baseBackoff = 500 * time.Millisecond
+	maxBackoff  = 30 * time.Second
+)
+
+// Dispatcher fans a single Event out to every matching Subscription,
+// signing each delivery with HMAC-SHA256 and retrying with exponential
+// backoff and jitter before giving up and dead-lettering it to the log.
+type Dispatcher struct {
+	secret string
+	client *http.Client
+
+	mu            sync.RWMutex
+	subscriptions []Subscription
+}
+
+// NewDispatcher builds a Dispatcher that signs callbacks with secret and
+// starts out subscribed to urls with no filtering (e.g. from
+// utils.Config.WebhookURLs); more can be added at runtime via Subscribe.
+func NewDispatcher(secret string, urls []string) *Dispatcher {
+	d := &Dispatcher{
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, url := range urls {
+		if url == "" {
+			continue
+		}
+		d.subscriptions = append(d.subscriptions, Subscription{URL: url})
+	}
+	return d
+}
+
+// Subscribe registers a new callback subscription at runtime.
+func (d *Dispatcher) Subscribe(sub Subscription) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscriptions = append(d.subscriptions, sub)
+}
+
+// Dispatch delivers event to every matching subscription in its own
+// goroutine, so a slow or unreachable endpoint never blocks the request
+// that triggered the event.
+func (d *Dispatcher) Dispatch(event Event) {
+	d.mu.RLock()
+	subs := make([]Subscription, len(d.subscriptions))
+	copy(subs, d.subscriptions)
+	d.mu.RUnlock()
+
+	if len(subs) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("webhooks: marshalling event %+v: %v", event, err)
+		return
+	}
+	signature := d.sign(body)
+
+	for _, sub := range subs {
+		if !sub.Matches(event) {
+			continue
+		}
+		go d.deliver(sub.URL, body, signature)
+	}
+}
+
+func (d *Dispatcher) sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(d.secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver POSTs body to url, retrying with backoff until maxAttempts is
+// reached, at which point the delivery is logged as dead-lettered.
+func (d *Dispatcher) deliver(url string, body []byte, signature string) {
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(backoff(attempt - 1))
+		}
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			lastErr = err
+			break
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Webhook-Signature", "sha256="+signature)
+
+		resp, err := d.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return
+		}
+		lastErr = fmt.Errorf("received status %d", resp.StatusCode)
+	}
+	log.Printf("webhooks: dead-lettering delivery to %s after %d attempts: %v", url, maxAttempts, lastErr)
+}
+
+// backoff returns the delay to wait after failed attempt number attempt,
+// before the next one (so backoff(1) precedes the first retry), doubling each
+// time up to maxBackoff and adding up to 50% jitter so many failing
+// deliveries don't retry in lockstep.
+func backoff(attempt int) time.Duration {
+	delay := baseBackoff * time.Duration(1<<uint(attempt-1))
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay + jitter
+}
@@ -0,0 +1,67 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestDispatcherSign(t *testing.T) {
+	d := NewDispatcher("shh", nil)
+	body := []byte(`{"type":"object.completed"}`)
+
+	got := d.sign(body)
+
+	mac := hmac.New(sha256.New, []byte("shh"))
+	mac.Write(body)
+	want := hex.EncodeToString(mac.Sum(nil))
+
+	if got != want {
+		t.Fatalf("sign() = %q, want %q", got, want)
+	}
+
+	if again := d.sign(body); again != got {
+		t.Errorf("sign() is not deterministic: %q != %q", again, got)
+	}
+
+	other := NewDispatcher("different", nil)
+	if other.sign(body) == got {
+		t.Error("sign() produced the same signature for a different secret")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	var prevMax time.Duration
+	for attempt := 1; attempt <= 8; attempt++ {
+		delay := backoff(attempt)
+		if delay <= 0 {
+			t.Fatalf("backoff(%d) = %v, want positive", attempt, delay)
+		}
+
+		base := baseBackoff * time.Duration(int64(1)<<uint(attempt-1))
+		if base > maxBackoff {
+			base = maxBackoff
+		}
+		maxWithJitter := base + base/2 + 1
+		if delay > maxWithJitter {
+			t.Errorf("backoff(%d) = %v, want <= %v", attempt, delay, maxWithJitter)
+		}
+		if delay < base {
+			t.Errorf("backoff(%d) = %v, want >= base %v", attempt, delay, base)
+		}
+
+		if attempt > 1 && base < prevMax {
+			t.Errorf("backoff base did not grow monotonically at attempt %d", attempt)
+		}
+		prevMax = base
+	}
+}
+
+func TestBackoffCapsAtMaxBackoff(t *testing.T) {
+	delay := backoff(20)
+	if delay > maxBackoff+maxBackoff/2+1 {
+		t.Errorf("backoff(20) = %v, want capped near maxBackoff %v", delay, maxBackoff)
+	}
+}
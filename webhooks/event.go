@@ -0,0 +1,27 @@
+// Package webhooks notifies downstream services (thumbnailers, virus
+// scanners, indexers) about object changes via signed HTTP callbacks, so
+// they don't have to poll a bucket to find out what changed.
+package webhooks
+
+import "time"
+
+// EventType identifies what happened to an object.
+type EventType string
+
+const (
+	EventObjectCompleted EventType = "object.completed"
+	EventObjectRemoved   EventType = "object.removed"
+	EventObjectRenamed   EventType = "object.renamed"
+)
+
+// Event describes one object-lifecycle change, delivered as the JSON body
+// of a webhook callback and signed over exactly as marshalled.
+type Event struct {
+	Type      EventType `json:"type"`
+	Bucket    string    `json:"bucket"`
+	Key       string    `json:"key"`
+	Size      int64     `json:"size,omitempty"`
+	ETag      string    `json:"eTag,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"requestId"`
+}
@@ -0,0 +1,31 @@
+package webhooks
+
+import "strings"
+
+// Subscription is one registered callback URL, optionally filtered to a
+// key prefix and/or a set of event types.
+type Subscription struct {
+	URL string `json:"url"`
+	// KeyPrefix, when set, limits delivery to events whose key starts
+	// with it.
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+	// EventTypes, when non-empty, limits delivery to these event types;
+	// empty means every event type.
+	EventTypes []EventType `json:"eventTypes,omitempty"`
+}
+
+// Matches reports whether event should be delivered to this subscription.
+func (s Subscription) Matches(event Event) bool {
+	if s.KeyPrefix != "" && !strings.HasPrefix(event.Key, s.KeyPrefix) {
+		return false
+	}
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, eventType := range s.EventTypes {
+		if eventType == event.Type {
+			return true
+		}
+	}
+	return false
+}